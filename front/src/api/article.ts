@@ -115,6 +115,20 @@ export const articleInfoByID = (id: string): Promise<ApiResponse<Article>> => {
     });
 }
 
+export interface ArticleInfoBySlug {
+    id: string;
+    slug: string;
+    redirected: boolean;
+    article: Article;
+}
+
+export const articleInfoBySlug = (slug: string): Promise<ApiResponse<ArticleInfoBySlug>> => {
+    return service({
+        url: '/article/slug/'+encodeURIComponent(slug),
+        method: 'get',
+    });
+}
+
 export interface ArticleSearchRequest extends PageInfo {
     query: string;
     category: string;
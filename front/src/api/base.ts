@@ -15,6 +15,7 @@ export const captcha = (): Promise<ApiResponse<CaptchaResponse>> => {
 
 export interface EmailRequest {
   email: string;
+  purpose: 'register' | 'forgot_password' | 'change_email';
   captcha: string;
   captcha_id: string;
 }
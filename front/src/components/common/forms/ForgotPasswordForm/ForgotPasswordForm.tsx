@@ -22,6 +22,7 @@ const ForgotPasswordFormComponent: React.FC = () => {
   const [repeatPassword, setRepeatPassword] = useState('');
   const [emailRequest, setEmailRequest] = useState<EmailRequest>({
     email: '',
+    purpose: 'forgot_password',
     captcha: '',
     captcha_id: '',
   });
@@ -35,6 +35,7 @@ const RegisterFormComponent: React.FC = () => {
   const [repeatPassword, setRepeatPassword] = useState('');
   const [emailRequest, setEmailRequest] = useState<EmailRequest>({
     email: '',
+    purpose: 'register',
     captcha: '',
     captcha_id: '',
   });
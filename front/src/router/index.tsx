@@ -1,7 +1,8 @@
 import React, { useEffect } from 'react';
-import { Routes, Route, useNavigate, Navigate } from 'react-router-dom';
+import { Routes, Route, useNavigate, useParams, Navigate } from 'react-router-dom';
 import { message, Modal } from 'antd';
 import useUserStore from '@/stores/user';
+import { articleInfoBySlug } from '@/api/article';
 import Index from "@/pages/web/index/index"
 import Article from '@/pages/web/article/article';
 import Feedback from '@/pages/web/feedback/feedback';
@@ -56,6 +57,20 @@ const AdminRoute = ({ children }: { children: React.ReactNode }) => {
   return userStore.isAdmin() ? children : null;
 };
 
+// 文章永久链接，根据别名找到文章后跳转到文章页
+const ArticleSlugRoute = () => {
+  const { slug } = useParams<{ slug: string }>();
+  const navigate = useNavigate();
+
+  useEffect(() => {
+    articleInfoBySlug(slug!).then(res => {
+      navigate(res.code === 0 ? `/article/${res.data.id}` : '/404', { replace: true });
+    });
+  }, [slug, navigate]);
+
+  return null;
+};
+
 const AppRouter = () => {
   return (
     <Routes>
@@ -66,6 +81,7 @@ const AppRouter = () => {
       <Route path="friend-link" element={<FriendLink />} />
       {/* <Route path="about" element={<About />} /> */}
       <Route path="article/:id" element={<Article />} />
+      <Route path="article/s/:slug" element={<ArticleSlugRoute />} />
 
       <Route path="dashboard" element={
         <PrivateRoute>
@@ -1,11 +1,13 @@
 package api
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 	"server/global"
 	"server/model/request"
 	"server/model/response"
+	"server/service"
 	"server/utils"
 )
 
@@ -22,7 +24,7 @@ func (articleApi *ArticleApi) ArticleInfoByID(c *gin.Context) {
 		return
 	}
 
-	article, err := articleService.ArticleInfoByID(req.ID)
+	article, err := articleService.ArticleInfoByID(req.ID, utils.GetVisitor(c))
 	if err != nil {
 		global.Log.Error("Failed to get article information:", zap.Error(err))
 		response.FailWithMessage("Failed to get article information", c)
@@ -31,6 +33,42 @@ func (articleApi *ArticleApi) ArticleInfoByID(c *gin.Context) {
 	response.OkWithData(article, c)
 }
 
+// ArticleInfoBySlug 根据文章别名获取文章内容
+func (articleApi *ArticleApi) ArticleInfoBySlug(c *gin.Context) {
+	var req request.ArticleInfoBySlug
+	err := c.ShouldBindUri(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	info, err := articleService.ArticleInfoBySlug(req.Slug, utils.GetVisitor(c))
+	if err != nil {
+		global.Log.Error("Failed to get article information:", zap.Error(err))
+		response.FailWithMessage("Failed to get article information", c)
+		return
+	}
+	response.OkWithData(info, c)
+}
+
+// ArticleRelated 获取相关文章
+func (articleApi *ArticleApi) ArticleRelated(c *gin.Context) {
+	var req request.ArticleInfoByID
+	err := c.ShouldBindUri(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	list, err := articleService.ArticleRelated(req.ID)
+	if err != nil {
+		global.Log.Error("Failed to get related articles:", zap.Error(err))
+		response.FailWithMessage("Failed to get related articles", c)
+		return
+	}
+	response.OkWithData(list, c)
+}
+
 // ArticleSearch 文章搜索
 func (articleApi *ArticleApi) ArticleSearch(c *gin.Context) {
 	var info request.ArticleSearch
@@ -52,6 +90,24 @@ func (articleApi *ArticleApi) ArticleSearch(c *gin.Context) {
 	}, c)
 }
 
+// ArticleSuggest 文章标题自动补全
+func (articleApi *ArticleApi) ArticleSuggest(c *gin.Context) {
+	var req request.ArticleSuggest
+	err := c.ShouldBindQuery(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	list, err := articleService.ArticleSuggest(req.Query)
+	if err != nil {
+		global.Log.Error("Failed to get article suggestions:", zap.Error(err))
+		response.FailWithMessage("Failed to get article suggestions", c)
+		return
+	}
+	response.OkWithData(list, c)
+}
+
 // ArticleCategory 获取所有文章类别及数量
 func (articleApi *ArticleApi) ArticleCategory(c *gin.Context) {
 	category, err := articleService.ArticleCategory()
@@ -143,6 +199,7 @@ func (articleApi *ArticleApi) ArticleCreate(c *gin.Context) {
 		return
 	}
 
+	req.UserID = utils.GetUserID(c)
 	err = articleService.ArticleCreate(req)
 	if err != nil {
 		global.Log.Error("Failed to create article:", zap.Error(err))
@@ -179,6 +236,7 @@ func (articleApi *ArticleApi) ArticleUpdate(c *gin.Context) {
 		return
 	}
 
+	req.UserID = utils.GetUserID(c)
 	err = articleService.ArticleUpdate(req)
 	if err != nil {
 		global.Log.Error("Failed to update article:", zap.Error(err))
@@ -208,3 +266,111 @@ func (articleApi *ArticleApi) ArticleList(c *gin.Context) {
 		Total: total,
 	}, c)
 }
+
+// ArticleRevisionList 获取文章修订记录列表
+func (articleApi *ArticleApi) ArticleRevisionList(c *gin.Context) {
+	var pageInfo request.ArticleRevisionList
+	err := c.ShouldBindQuery(&pageInfo)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	list, total, err := articleService.ArticleRevisionList(pageInfo)
+	if err != nil {
+		global.Log.Error("Failed to get article revision list:", zap.Error(err))
+		response.FailWithMessage("Failed to get article revision list", c)
+		return
+	}
+	response.OkWithData(response.PageResult{
+		List:  list,
+		Total: total,
+	}, c)
+}
+
+// ArticleRevisionDiff 比较文章的两个版本
+func (articleApi *ArticleApi) ArticleRevisionDiff(c *gin.Context) {
+	var req request.ArticleRevisionDiff
+	err := c.ShouldBindQuery(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	diff, err := articleService.ArticleRevisionDiff(req)
+	if err != nil {
+		global.Log.Error("Failed to get article revision diff:", zap.Error(err))
+		response.FailWithMessage("Failed to get article revision diff", c)
+		return
+	}
+	response.OkWithData(diff, c)
+}
+
+// ArticleRevisionRestore 将文章恢复到指定版本
+func (articleApi *ArticleApi) ArticleRevisionRestore(c *gin.Context) {
+	var req request.ArticleRevisionRestore
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	req.UserID = utils.GetUserID(c)
+	err = articleService.ArticleRevisionRestore(req)
+	if err != nil {
+		global.Log.Error("Failed to restore article revision:", zap.Error(err))
+		response.FailWithMessage("Failed to restore article revision", c)
+		return
+	}
+	response.OkWithMessage("Successfully restored article revision", c)
+}
+
+// ArticleStats 获取文章的统计数据，每日浏览量、独立访客、收藏、评论以及来源
+func (articleApi *ArticleApi) ArticleStats(c *gin.Context) {
+	var uri request.ArticleInfoByID
+	err := c.ShouldBindUri(&uri)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	var req request.ArticleStats
+	err = c.ShouldBindQuery(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	data, err := articleService.ArticleStats(uri.ID, req)
+	if errors.Is(err, service.ErrStatsRangeInvalid) {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if err != nil {
+		global.Log.Error("Failed to get article stats:", zap.Error(err))
+		response.FailWithMessage("Failed to get article stats", c)
+		return
+	}
+	response.OkWithData(data, c)
+}
+
+// ArticleStatsTop 获取指定指标最高的文章
+func (articleApi *ArticleApi) ArticleStatsTop(c *gin.Context) {
+	var req request.ArticleStatsTop
+	err := c.ShouldBindQuery(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	list, err := articleService.ArticleStatsTop(req)
+	if errors.Is(err, service.ErrStatsRangeInvalid) {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if err != nil {
+		global.Log.Error("Failed to get top articles:", zap.Error(err))
+		response.FailWithMessage("Failed to get top articles", c)
+		return
+	}
+	response.OkWithData(list, c)
+}
@@ -1,12 +1,15 @@
 package api
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/mojocn/base64Captcha"
 	"go.uber.org/zap"
 	"server/global"
+	"server/model/appTypes"
 	"server/model/request"
 	"server/model/response"
+	"server/service"
 )
 
 // BaseApi 结构体是一个空结构体，用于组织 API 方法，这样可以更方便地管理和扩展 API 逻辑
@@ -68,7 +71,12 @@ func (baseApi *BaseApi) SendEmailVerificationCode(c *gin.Context) {
 	// 验证用户输入的验证码是否正确
 	if store.Verify(req.CaptchaID, req.Captcha, true) {
 		// 若验证码验证通过，调用 baseService 的 SendEmailVerificationCode 方法发送邮箱验证码
-		err = baseService.SendEmailVerificationCode(c, req.Email)
+		err = baseService.SendEmailVerificationCode(req.Email, req.Purpose, c.ClientIP())
+		// 发送过于频繁时提示用户稍后再试
+		if errors.Is(err, service.ErrVerificationTooFrequent) {
+			response.FailWithMessage("Verification codes are requested too frequently, please try again later", c)
+			return
+		}
 		// 检查发送邮箱验证码过程中是否出现错误
 		if err != nil {
 			// 若出现错误，使用全局日志记录器记录错误信息
@@ -92,3 +100,22 @@ func (baseApi *BaseApi) QQLoginURL(c *gin.Context) {
 	// 向客户端返回包含 QQ 登录链接的成功响应
 	response.OkWithData(url, c)
 }
+
+// verifyEmailCode 校验并消费邮箱验证码，校验失败时直接返回失败响应
+func verifyEmailCode(c *gin.Context, email string, purpose appTypes.VerificationPurpose, code string) bool {
+	err := baseService.VerifyEmailCode(email, purpose, code)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, service.ErrVerificationCodeInvalid):
+		response.FailWithMessage("Invalid verification code", c)
+	case errors.Is(err, service.ErrVerificationCodeExpired):
+		response.FailWithMessage("The verification code has expired, please resend it", c)
+	case errors.Is(err, service.ErrVerificationCodeLocked):
+		response.FailWithMessage("Too many failed attempts, please resend the verification code", c)
+	default:
+		global.Log.Error("Failed to verify email verification code:", zap.Error(err))
+		response.FailWithMessage("Failed to verify email verification code", c)
+	}
+	return false
+}
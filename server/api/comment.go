@@ -1,12 +1,16 @@
 package api
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 	"server/global"
+	"server/model/appTypes"
 	"server/model/request"
 	"server/model/response"
+	"server/service"
 	"server/utils"
+	"server/utils/contentFilter"
 )
 
 type CommentApi struct {
@@ -20,6 +24,11 @@ func (commentApi *CommentApi) CommentInfoByArticleID(c *gin.Context) {
 		response.FailWithMessage(err.Error(), c)
 		return
 	}
+	err = c.ShouldBindQuery(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
 
 	list, err := commentService.CommentInfoByArticleID(req)
 	if err != nil {
@@ -51,15 +60,72 @@ func (commentApi *CommentApi) CommentCreate(c *gin.Context) {
 	}
 
 	req.UserUUID = utils.GetUUID(c)
-	err = commentService.CommentCreate(req)
+	req.RoleID = utils.GetRoleID(c)
+	status, err := commentService.CommentCreate(req)
+	if errors.Is(err, contentFilter.ErrRejected) {
+		response.FailWithMessage("Comment contains prohibited content", c)
+		return
+	}
 	if err != nil {
 		global.Log.Error("Failed to create comment:", zap.Error(err))
 		response.FailWithMessage("Failed to create comment", c)
 		return
 	}
+	if status == appTypes.CommentPending {
+		response.OkWithMessage("Comment submitted, awaiting review", c)
+		return
+	}
 	response.OkWithMessage("Successfully created comment", c)
 }
 
+// CommentUpdate 编辑评论
+func (commentApi *CommentApi) CommentUpdate(c *gin.Context) {
+	var req request.CommentUpdate
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	req.UserUUID = utils.GetUUID(c)
+	req.RoleID = utils.GetRoleID(c)
+	err = commentService.CommentUpdate(req)
+	if errors.Is(err, contentFilter.ErrRejected) {
+		response.FailWithMessage("Comment contains prohibited content", c)
+		return
+	}
+	if errors.Is(err, service.ErrCommentEditExpired) {
+		response.FailWithMessage("Comment can no longer be edited", c)
+		return
+	}
+	if err != nil {
+		global.Log.Error("Failed to update comment:", zap.Error(err))
+		response.FailWithMessage("Failed to update comment", c)
+		return
+	}
+	response.OkWithMessage("Successfully updated comment", c)
+}
+
+// CommentHistory 获取评论的编辑历史
+func (commentApi *CommentApi) CommentHistory(c *gin.Context) {
+	var req request.CommentHistory
+	err := c.ShouldBindQuery(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	req.UserUUID = utils.GetUUID(c)
+	req.RoleID = utils.GetRoleID(c)
+	list, err := commentService.CommentHistory(req)
+	if err != nil {
+		global.Log.Error("Failed to get comment history:", zap.Error(err))
+		response.FailWithMessage("Failed to get comment history", c)
+		return
+	}
+	response.OkWithData(list, c)
+}
+
 // CommentDelete 删除评论
 func (commentApi *CommentApi) CommentDelete(c *gin.Context) {
 	var req request.CommentDelete
@@ -109,4 +175,60 @@ func (commentApi *CommentApi) CommentList(c *gin.Context) {
 		List:  list,
 		Total: total,
 	}, c)
-}
\ No newline at end of file
+}
+
+// CommentModerate 批量审核评论
+func (commentApi *CommentApi) CommentModerate(c *gin.Context) {
+	var req request.CommentModerate
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	err = commentService.CommentModerate(req)
+	if err != nil {
+		global.Log.Error("Failed to moderate comments:", zap.Error(err))
+		response.FailWithMessage("Failed to moderate comments", c)
+		return
+	}
+	response.OkWithMessage("Successfully moderated comments", c)
+}
+
+// CommentReact 回应评论
+func (commentApi *CommentApi) CommentReact(c *gin.Context) {
+	var req request.CommentReact
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	req.UserID = utils.GetUserID(c)
+	req.UserUUID = utils.GetUUID(c)
+	err = commentService.CommentReact(req)
+	if err != nil {
+		global.Log.Error("Failed to react to comment:", zap.Error(err))
+		response.FailWithMessage("Failed to react to comment", c)
+		return
+	}
+	response.OkWithMessage("Successfully reacted to comment", c)
+}
+
+// CommentPin 置顶评论，文章由管理员发布，因此只有管理员可以置顶
+func (commentApi *CommentApi) CommentPin(c *gin.Context) {
+	var req request.CommentPin
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	err = commentService.CommentPin(req)
+	if err != nil {
+		global.Log.Error("Failed to pin comment:", zap.Error(err))
+		response.FailWithMessage("Failed to pin comment", c)
+		return
+	}
+	response.OkWithMessage("Successfully pinned comment", c)
+}
@@ -170,4 +170,73 @@ func (configApi *ConfigApi) UpdateGaode(c *gin.Context) {
 		return
 	}
 	response.OkWithMessage("Successfully updated gaode", c)
-}
\ No newline at end of file
+}
+
+// GetComment 获取评论配置
+func (configApi *ConfigApi) GetComment(c *gin.Context) {
+	response.OkWithData(global.Config.Comment, c)
+}
+
+// UpdateComment 更新评论配置
+func (configApi *ConfigApi) UpdateComment(c *gin.Context) {
+	var req config.Comment
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	err = configService.UpdateComment(req)
+	if err != nil {
+		global.Log.Error("Failed to update comment:", zap.Error(err))
+		response.FailWithMessage("Failed to update comment", c)
+		return
+	}
+	response.OkWithMessage("Successfully updated comment", c)
+}
+
+// GetFilter 获取内容过滤配置
+func (configApi *ConfigApi) GetFilter(c *gin.Context) {
+	response.OkWithData(global.Config.Filter, c)
+}
+
+// UpdateFilter 更新内容过滤配置
+func (configApi *ConfigApi) UpdateFilter(c *gin.Context) {
+	var req config.Filter
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	err = configService.UpdateFilter(req)
+	if err != nil {
+		global.Log.Error("Failed to update filter:", zap.Error(err))
+		response.FailWithMessage("Failed to update filter", c)
+		return
+	}
+	response.OkWithMessage("Successfully updated filter", c)
+}
+
+// GetNewsletter 获取邮件订阅配置
+func (configApi *ConfigApi) GetNewsletter(c *gin.Context) {
+	response.OkWithData(global.Config.Newsletter, c)
+}
+
+// UpdateNewsletter 更新邮件订阅配置
+func (configApi *ConfigApi) UpdateNewsletter(c *gin.Context) {
+	var req config.Newsletter
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	err = configService.UpdateNewsletter(req)
+	if err != nil {
+		global.Log.Error("Failed to update newsletter:", zap.Error(err))
+		response.FailWithMessage("Failed to update newsletter", c)
+		return
+	}
+	response.OkWithMessage("Successfully updated newsletter", c)
+}
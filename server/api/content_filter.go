@@ -0,0 +1,90 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"server/global"
+	"server/model/request"
+	"server/model/response"
+)
+
+type ContentFilterApi struct {
+}
+
+// SensitiveWordList 获取敏感词列表
+func (contentFilterApi *ContentFilterApi) SensitiveWordList(c *gin.Context) {
+	var pageInfo request.SensitiveWordList
+	err := c.ShouldBindQuery(&pageInfo)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	list, total, err := contentFilterService.SensitiveWordList(pageInfo)
+	if err != nil {
+		global.Log.Error("Failed to get sensitive word list:", zap.Error(err))
+		response.FailWithMessage("Failed to get sensitive word list", c)
+		return
+	}
+	response.OkWithData(response.PageResult{
+		List:  list,
+		Total: total,
+	}, c)
+}
+
+// SensitiveWordCreate 添加敏感词
+func (contentFilterApi *ContentFilterApi) SensitiveWordCreate(c *gin.Context) {
+	var req request.SensitiveWordCreate
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	err = contentFilterService.SensitiveWordCreate(req)
+	if err != nil {
+		global.Log.Error("Failed to create sensitive words:", zap.Error(err))
+		response.FailWithMessage("Failed to create sensitive words", c)
+		return
+	}
+	response.OkWithMessage("Successfully created sensitive words", c)
+}
+
+// SensitiveWordDelete 删除敏感词
+func (contentFilterApi *ContentFilterApi) SensitiveWordDelete(c *gin.Context) {
+	var req request.SensitiveWordDelete
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	err = contentFilterService.SensitiveWordDelete(req)
+	if err != nil {
+		global.Log.Error("Failed to delete sensitive words:", zap.Error(err))
+		response.FailWithMessage("Failed to delete sensitive words", c)
+		return
+	}
+	response.OkWithMessage("Successfully deleted sensitive words", c)
+}
+
+// FilterLogList 获取内容过滤记录
+func (contentFilterApi *ContentFilterApi) FilterLogList(c *gin.Context) {
+	var pageInfo request.FilterLogList
+	err := c.ShouldBindQuery(&pageInfo)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	list, total, err := contentFilterService.FilterLogList(pageInfo)
+	if err != nil {
+		global.Log.Error("Failed to get filter log list:", zap.Error(err))
+		response.FailWithMessage("Failed to get filter log list", c)
+		return
+	}
+	response.OkWithData(response.PageResult{
+		List:  list,
+		Total: total,
+	}, c)
+}
@@ -14,6 +14,15 @@ type ApiGroup struct {
 	FeedbackApi
 	WebsiteApi
 	ConfigApi
+	FeedApi
+	SitemapApi
+	OutboxApi
+	TrafficApi
+	ContentFilterApi
+	NotificationApi
+	StreamApi
+	MailApi
+	NewsletterApi
 }
 
 var ApiGroupApp = new(ApiGroup)
@@ -31,3 +40,12 @@ var friendLinkService = service.ServiceGroupApp.FriendLinkService
 var feedbackService = service.ServiceGroupApp.FeedbackService
 var websiteService = service.ServiceGroupApp.WebsiteService
 var configService = service.ServiceGroupApp.ConfigService
+var feedService = service.ServiceGroupApp.FeedService
+var sitemapService = service.ServiceGroupApp.SitemapService
+var outboxService = service.ServiceGroupApp.OutboxService
+var trafficService = service.ServiceGroupApp.TrafficService
+var contentFilterService = service.ServiceGroupApp.ContentFilterService
+var notificationService = service.ServiceGroupApp.NotificationService
+var streamService = service.ServiceGroupApp.StreamService
+var mailService = service.ServiceGroupApp.MailService
+var newsletterService = service.ServiceGroupApp.NewsletterService
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"server/global"
+	"server/model/request"
+	"server/utils"
+)
+
+type FeedApi struct {
+}
+
+// Feed 获取文章订阅源，支持 RSS 2.0、Atom 和 JSON Feed 三种格式
+func (feedApi *FeedApi) Feed(c *gin.Context) {
+	var req request.Feed
+	err := c.ShouldBindUri(&req)
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	siteURL := utils.SiteURL(c)
+	body, contentType, lastModified, err := feedService.Feed(req, siteURL, siteURL+c.Request.URL.Path)
+	if err != nil {
+		global.Log.Error("Failed to generate feed:", zap.Error(err))
+		c.String(http.StatusInternalServerError, "Failed to generate feed")
+		return
+	}
+	utils.WriteConditional(c, contentType, body, lastModified)
+}
@@ -1,12 +1,14 @@
 package api
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 	"server/global"
 	"server/model/request"
 	"server/model/response"
 	"server/utils"
+	"server/utils/contentFilter"
 )
 
 type FeedbackApi struct {
@@ -34,6 +36,10 @@ func (feedbackApi *FeedbackApi) FeedbackCreate(c *gin.Context) {
 
 	req.UUID = utils.GetUUID(c)
 	err = feedbackService.FeedbackCreate(req)
+	if errors.Is(err, contentFilter.ErrRejected) {
+		response.FailWithMessage("Feedback contains prohibited content", c)
+		return
+	}
 	if err != nil {
 		global.Log.Error("Failed to create feedback:", zap.Error(err))
 		response.FailWithMessage("Failed to create feedback", c)
@@ -72,6 +78,24 @@ func (feedbackApi *FeedbackApi) FeedbackDelete(c *gin.Context) {
 	response.OkWithMessage("Successfully deleted feedback", c)
 }
 
+// FeedbackApprove 审核通过反馈
+func (feedbackApi *FeedbackApi) FeedbackApprove(c *gin.Context) {
+	var req request.FeedbackApprove
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	err = feedbackService.FeedbackApprove(req)
+	if err != nil {
+		global.Log.Error("Failed to approve feedback:", zap.Error(err))
+		response.FailWithMessage("Failed to approve feedback", c)
+		return
+	}
+	response.OkWithMessage("Successfully approved feedback", c)
+}
+
 // FeedbackReply 回复反馈
 func (feedbackApi *FeedbackApi) FeedbackReply(c *gin.Context) {
 	var req request.FeedbackReply
@@ -81,6 +105,7 @@ func (feedbackApi *FeedbackApi) FeedbackReply(c *gin.Context) {
 		return
 	}
 
+	req.UUID = utils.GetUUID(c)
 	err = feedbackService.FeedbackReply(req)
 	if err != nil {
 		global.Log.Error("Failed to update feedback:", zap.Error(err))
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"server/global"
+	"server/model/request"
+	"server/model/response"
+)
+
+type MailApi struct {
+}
+
+// MailTemplateList 获取邮件模板列表
+func (mailApi *MailApi) MailTemplateList(c *gin.Context) {
+	list, err := mailService.MailTemplateList()
+	if err != nil {
+		global.Log.Error("Failed to get mail template list:", zap.Error(err))
+		response.FailWithMessage("Failed to get mail template list", c)
+		return
+	}
+	response.OkWithData(list, c)
+}
+
+// MailTemplateUpdate 修改邮件模板
+func (mailApi *MailApi) MailTemplateUpdate(c *gin.Context) {
+	var req request.MailTemplateUpdate
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	err = mailService.MailTemplateUpdate(req)
+	if err != nil {
+		global.Log.Error("Failed to update mail template:", zap.Error(err))
+		response.FailWithMessage("Failed to update mail template: "+err.Error(), c)
+		return
+	}
+	response.OkWithMessage("Successfully updated mail template", c)
+}
+
+// MailTemplateReset 恢复默认邮件模板
+func (mailApi *MailApi) MailTemplateReset(c *gin.Context) {
+	var req request.MailTemplateReset
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	err = mailService.MailTemplateReset(req)
+	if err != nil {
+		global.Log.Error("Failed to reset mail template:", zap.Error(err))
+		response.FailWithMessage("Failed to reset mail template", c)
+		return
+	}
+	response.OkWithMessage("Successfully reset mail template", c)
+}
+
+// MailLogList 获取邮件投递记录
+func (mailApi *MailApi) MailLogList(c *gin.Context) {
+	var pageInfo request.MailLogList
+	err := c.ShouldBindQuery(&pageInfo)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	list, total, err := mailService.MailLogList(pageInfo)
+	if err != nil {
+		global.Log.Error("Failed to get mail log list:", zap.Error(err))
+		response.FailWithMessage("Failed to get mail log list", c)
+		return
+	}
+	response.OkWithData(response.PageResult{
+		List:  list,
+		Total: total,
+	}, c)
+}
+
+// MailDeadList 获取死信队列中的邮件
+func (mailApi *MailApi) MailDeadList(c *gin.Context) {
+	list, err := mailService.MailDeadList()
+	if err != nil {
+		global.Log.Error("Failed to get dead mail list:", zap.Error(err))
+		response.FailWithMessage("Failed to get dead mail list", c)
+		return
+	}
+	response.OkWithData(list, c)
+}
+
+// MailDeadRetry 重新发送死信队列中的邮件
+func (mailApi *MailApi) MailDeadRetry(c *gin.Context) {
+	var req request.MailDeadRetry
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	err = mailService.MailDeadRetry(req)
+	if err != nil {
+		global.Log.Error("Failed to retry dead mails:", zap.Error(err))
+		response.FailWithMessage("Failed to retry dead mails", c)
+		return
+	}
+	response.OkWithMessage("Successfully queued dead mails for retry", c)
+}
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"html/template"
+	"net/http"
+	"server/global"
+	"server/model/request"
+	"server/model/response"
+	"server/service"
+)
+
+type NewsletterApi struct {
+}
+
+// newsletterPage 确认订阅和退订页面，GET 请求只展示页面，由用户提交表单后才修改订阅状态，避免邮件安全扫描器访问链接时误操作
+var newsletterPage = template.Must(template.New("newsletter").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>{{.Site}}</title></head>
+<body>
+<p>{{.Message}}</p>
+{{if .Button}}<form method="post" action="{{.Action}}">
+<input type="hidden" name="email" value="{{.Email}}">
+<input type="hidden" name="sig" value="{{.Signature}}">
+<button type="submit">{{.Button}}</button>
+</form>{{end}}
+</body>
+</html>`))
+
+// newsletterPageData 订阅页面的数据，Button 为空时只展示提示信息
+type newsletterPageData struct {
+	Site      string
+	Message   string
+	Action    string
+	Email     string
+	Signature string
+	Button    string
+}
+
+// renderNewsletterPage 渲染订阅页面
+func renderNewsletterPage(c *gin.Context, status int, data newsletterPageData) {
+	data.Site = global.Config.Website.Title
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+	if err := newsletterPage.Execute(c.Writer, data); err != nil {
+		global.Log.Error("Failed to render newsletter page:", zap.Error(err))
+	}
+}
+
+// NewsletterSubscribe 订阅新文章，发送确认邮件
+func (newsletterApi *NewsletterApi) NewsletterSubscribe(c *gin.Context) {
+	var req request.NewsletterSubscribe
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+	if !store.Verify(req.CaptchaID, req.Captcha, true) {
+		response.FailWithMessage("Incorrect verification code", c)
+		return
+	}
+
+	err = newsletterService.Subscribe(req, c.ClientIP())
+	if errors.Is(err, service.ErrNewsletterTooFrequent) {
+		response.FailWithMessage("Subscriptions are requested too frequently, please try again later", c)
+		return
+	}
+	if err != nil {
+		global.Log.Error("Failed to subscribe:", zap.Error(err))
+		response.FailWithMessage("Failed to subscribe", c)
+		return
+	}
+	response.OkWithMessage("Please check your email to confirm the subscription", c)
+}
+
+// NewsletterConfirmPage 展示确认订阅的页面
+func (newsletterApi *NewsletterApi) NewsletterConfirmPage(c *gin.Context) {
+	var req request.NewsletterLink
+	if err := c.ShouldBindQuery(&req); err != nil {
+		renderNewsletterPage(c, http.StatusBadRequest, newsletterPageData{Message: service.ErrNewsletterLinkInvalid.Error()})
+		return
+	}
+	renderNewsletterPage(c, http.StatusOK, newsletterPageData{
+		Message:   "Confirm the subscription for " + req.Email,
+		Action:    c.Request.URL.Path,
+		Email:     req.Email,
+		Signature: req.Signature,
+		Button:    "Confirm",
+	})
+}
+
+// NewsletterConfirm 提交确认页面的表单完成订阅
+func (newsletterApi *NewsletterApi) NewsletterConfirm(c *gin.Context) {
+	var req request.NewsletterLink
+	if err := c.ShouldBind(&req); err != nil {
+		renderNewsletterPage(c, http.StatusBadRequest, newsletterPageData{Message: service.ErrNewsletterLinkInvalid.Error()})
+		return
+	}
+
+	err := newsletterService.Confirm(req)
+	if errors.Is(err, service.ErrNewsletterLinkInvalid) || errors.Is(err, service.ErrNewsletterLinkExpired) {
+		renderNewsletterPage(c, http.StatusBadRequest, newsletterPageData{Message: err.Error()})
+		return
+	}
+	if err != nil {
+		global.Log.Error("Failed to confirm subscription:", zap.Error(err))
+		renderNewsletterPage(c, http.StatusInternalServerError, newsletterPageData{Message: "Failed to confirm subscription"})
+		return
+	}
+	renderNewsletterPage(c, http.StatusOK, newsletterPageData{Message: "Successfully subscribed"})
+}
+
+// NewsletterUnsubscribePage 展示退订页面
+func (newsletterApi *NewsletterApi) NewsletterUnsubscribePage(c *gin.Context) {
+	var req request.NewsletterLink
+	if err := c.ShouldBindQuery(&req); err != nil {
+		renderNewsletterPage(c, http.StatusBadRequest, newsletterPageData{Message: service.ErrNewsletterLinkInvalid.Error()})
+		return
+	}
+	renderNewsletterPage(c, http.StatusOK, newsletterPageData{
+		Message:   "Unsubscribe " + req.Email + " from the newsletter",
+		Action:    c.Request.URL.Path,
+		Email:     req.Email,
+		Signature: req.Signature,
+		Button:    "Unsubscribe",
+	})
+}
+
+// NewsletterUnsubscribe 提交退订页面的表单取消订阅
+// 邮件带有 List-Unsubscribe-Post 头，邮件客户端一键退订时也会向退订链接发送 POST 请求，此时参数在查询字符串中
+func (newsletterApi *NewsletterApi) NewsletterUnsubscribe(c *gin.Context) {
+	var req request.NewsletterLink
+	if err := c.ShouldBind(&req); err != nil {
+		renderNewsletterPage(c, http.StatusBadRequest, newsletterPageData{Message: service.ErrNewsletterLinkInvalid.Error()})
+		return
+	}
+
+	err := newsletterService.Unsubscribe(req)
+	if errors.Is(err, service.ErrNewsletterLinkInvalid) {
+		renderNewsletterPage(c, http.StatusBadRequest, newsletterPageData{Message: err.Error()})
+		return
+	}
+	if err != nil {
+		global.Log.Error("Failed to unsubscribe:", zap.Error(err))
+		renderNewsletterPage(c, http.StatusInternalServerError, newsletterPageData{Message: "Failed to unsubscribe"})
+		return
+	}
+	renderNewsletterPage(c, http.StatusOK, newsletterPageData{Message: "Successfully unsubscribed"})
+}
+
+// SubscriberList 获取订阅者列表
+func (newsletterApi *NewsletterApi) SubscriberList(c *gin.Context) {
+	var pageInfo request.SubscriberList
+	err := c.ShouldBindQuery(&pageInfo)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	list, total, err := newsletterService.SubscriberList(pageInfo)
+	if err != nil {
+		global.Log.Error("Failed to get subscriber list:", zap.Error(err))
+		response.FailWithMessage("Failed to get subscriber list", c)
+		return
+	}
+	response.OkWithData(response.PageResult{
+		List:  list,
+		Total: total,
+	}, c)
+}
+
+// SubscriberDelete 删除订阅者
+func (newsletterApi *NewsletterApi) SubscriberDelete(c *gin.Context) {
+	var req request.SubscriberDelete
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	err = newsletterService.SubscriberDelete(req)
+	if err != nil {
+		global.Log.Error("Failed to delete subscribers:", zap.Error(err))
+		response.FailWithMessage("Failed to delete subscribers", c)
+		return
+	}
+	response.OkWithMessage("Successfully deleted subscribers", c)
+}
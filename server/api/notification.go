@@ -0,0 +1,95 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"server/global"
+	"server/model/request"
+	"server/model/response"
+	"server/utils"
+)
+
+type NotificationApi struct {
+}
+
+// NotificationList 获取通知列表
+func (notificationApi *NotificationApi) NotificationList(c *gin.Context) {
+	var pageInfo request.NotificationList
+	err := c.ShouldBindQuery(&pageInfo)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	pageInfo.UserUUID = utils.GetUUID(c)
+	list, total, err := notificationService.NotificationList(pageInfo)
+	if err != nil {
+		global.Log.Error("Failed to get notification list:", zap.Error(err))
+		response.FailWithMessage("Failed to get notification list", c)
+		return
+	}
+	response.OkWithData(response.PageResult{
+		List:  list,
+		Total: total,
+	}, c)
+}
+
+// NotificationUnread 获取未读通知数
+func (notificationApi *NotificationApi) NotificationUnread(c *gin.Context) {
+	count, err := notificationService.NotificationUnread(utils.GetUUID(c))
+	if err != nil {
+		global.Log.Error("Failed to get unread notification count:", zap.Error(err))
+		response.FailWithMessage("Failed to get unread notification count", c)
+		return
+	}
+	response.OkWithData(count, c)
+}
+
+// NotificationRead 将通知标记为已读
+func (notificationApi *NotificationApi) NotificationRead(c *gin.Context) {
+	var req request.NotificationRead
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	req.UserUUID = utils.GetUUID(c)
+	err = notificationService.NotificationRead(req)
+	if err != nil {
+		global.Log.Error("Failed to mark notifications as read:", zap.Error(err))
+		response.FailWithMessage("Failed to mark notifications as read", c)
+		return
+	}
+	response.OkWithMessage("Successfully marked notifications as read", c)
+}
+
+// NotificationPreferences 获取通知偏好
+func (notificationApi *NotificationApi) NotificationPreferences(c *gin.Context) {
+	list, err := notificationService.NotificationPreferences(utils.GetUUID(c))
+	if err != nil {
+		global.Log.Error("Failed to get notification preferences:", zap.Error(err))
+		response.FailWithMessage("Failed to get notification preferences", c)
+		return
+	}
+	response.OkWithData(list, c)
+}
+
+// NotificationPreferenceUpdate 更新通知偏好
+func (notificationApi *NotificationApi) NotificationPreferenceUpdate(c *gin.Context) {
+	var req request.NotificationPreferenceUpdate
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	req.UserUUID = utils.GetUUID(c)
+	err = notificationService.NotificationPreferenceUpdate(req)
+	if err != nil {
+		global.Log.Error("Failed to update notification preferences:", zap.Error(err))
+		response.FailWithMessage("Failed to update notification preferences", c)
+		return
+	}
+	response.OkWithMessage("Successfully updated notification preferences", c)
+}
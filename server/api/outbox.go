@@ -0,0 +1,51 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"server/global"
+	"server/model/request"
+	"server/model/response"
+)
+
+type OutboxApi struct {
+}
+
+// OutboxList 获取投递失败或正在重试的 ES 操作
+func (outboxApi *OutboxApi) OutboxList(c *gin.Context) {
+	var info request.OutboxList
+	err := c.ShouldBindQuery(&info)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	list, total, err := outboxService.OutboxList(info)
+	if err != nil {
+		global.Log.Error("Failed to get outbox list:", zap.Error(err))
+		response.FailWithMessage("Failed to get outbox list", c)
+		return
+	}
+	response.OkWithData(response.PageResult{
+		List:  list,
+		Total: total,
+	}, c)
+}
+
+// OutboxRetry 立即重试指定的 ES 操作
+func (outboxApi *OutboxApi) OutboxRetry(c *gin.Context) {
+	var req request.OutboxRetry
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	err = outboxService.OutboxRetry(req)
+	if err != nil {
+		global.Log.Error("Failed to retry outbox events:", zap.Error(err))
+		response.FailWithMessage("Failed to retry outbox events", c)
+		return
+	}
+	response.OkWithMessage("Successfully retried outbox events", c)
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"server/global"
+	"server/utils"
+	"strings"
+	"time"
+)
+
+type SitemapApi struct {
+}
+
+// Sitemap 获取站点地图入口，页面较多时为站点地图索引
+func (sitemapApi *SitemapApi) Sitemap(c *gin.Context) {
+	sitemapApi.writeSitemap(c, "sitemap.xml")
+}
+
+// SitemapPart 获取拆分后的站点地图
+func (sitemapApi *SitemapApi) SitemapPart(c *gin.Context) {
+	sitemapApi.writeSitemap(c, c.Param("name"))
+}
+
+// Robots 获取 robots.txt
+func (sitemapApi *SitemapApi) Robots(c *gin.Context) {
+	robots := sitemapService.Robots(strings.TrimRight(global.Config.Website.SiteURL, "/"))
+	c.String(http.StatusOK, robots)
+}
+
+func (sitemapApi *SitemapApi) writeSitemap(c *gin.Context, name string) {
+	sitemap, err := sitemapService.GetSitemap(name)
+	if err != nil {
+		global.Log.Error("Failed to get sitemap:", zap.Error(err))
+		c.String(http.StatusNotFound, "Sitemap not found")
+		return
+	}
+	utils.WriteConditional(c, "application/xml; charset=utf-8", sitemap, time.Time{})
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"server/model/appTypes"
+	"server/model/request"
+	"server/model/response"
+	"server/service"
+	"server/utils"
+	"time"
+)
+
+type StreamApi struct {
+}
+
+// streamHeartbeat 心跳间隔，防止代理因连接空闲而断开
+const streamHeartbeat = 30 * time.Second
+
+// Stream 通过 Server-Sent Events 推送文章新评论、个人通知以及管理员事件
+func (streamApi *StreamApi) Stream(c *gin.Context) {
+	var req request.Stream
+	err := c.ShouldBindQuery(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	channels := []string{service.StreamUserChannel(utils.GetUUID(c))}
+	if req.ArticleID != "" {
+		channels = append(channels, service.StreamArticleChannel(req.ArticleID))
+	}
+	if utils.GetRoleID(c) == appTypes.Admin {
+		channels = append(channels, service.StreamAdminChannel)
+	}
+
+	messages, unsubscribe := streamService.Subscribe(channels...)
+	defer unsubscribe()
+
+	// 长连接不受服务器写超时限制
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case message := <-messages:
+			c.SSEvent(string(message.Event), string(message.Data))
+		case <-heartbeat.C:
+			c.SSEvent("ping", time.Now().Unix())
+		}
+		return true
+	})
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"server/global"
+	"server/model/request"
+	"server/model/response"
+)
+
+type TrafficApi struct {
+}
+
+// TrafficStats 获取站点流量统计，热门页面、来源、地区和设备分布
+func (trafficApi *TrafficApi) TrafficStats(c *gin.Context) {
+	var req request.TrafficStats
+	err := c.ShouldBindQuery(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	data, err := trafficService.TrafficStats(req)
+	if err != nil {
+		global.Log.Error("Failed to get traffic stats:", zap.Error(err))
+		response.FailWithMessage("Failed to get traffic stats", c)
+		return
+	}
+	response.OkWithData(data, c)
+}
@@ -2,14 +2,15 @@ package api
 
 import (
 	"errors"
-	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
 	"github.com/go-redis/redis"
 	"go.uber.org/zap"
 	"server/global"
+	"server/model/appTypes"
 	"server/model/database"
 	"server/model/request"
 	"server/model/response"
+	"server/service"
 	"server/utils"
 	"time"
 )
@@ -28,25 +29,8 @@ func (userApi *UserApi) Register(c *gin.Context) {
 		return
 	}
 
-	session := sessions.Default(c)
-	// 两次邮箱一致性判断
-	savedEmail := session.Get("email")
-	if savedEmail == nil || savedEmail.(string) != req.Email {
-		response.FailWithMessage("This email doesn't match the email to be verified", c)
-		return
-	}
-
-	// 获取会话中存储的邮箱验证码
-	savedCode := session.Get("verification_code")
-	if savedCode == nil || savedCode.(string) != req.VerificationCode {
-		response.FailWithMessage("Invalid verification code", c)
-		return
-	}
-
-	// 判断邮箱验证码是否过期
-	savedTime := session.Get("expire_time")
-	if savedTime.(int64) < time.Now().Unix() {
-		response.FailWithMessage("The verification code has expired, please resend it", c)
+	// 校验并消费注册验证码
+	if !verifyEmailCode(c, req.Email, appTypes.VerificationRegister, req.VerificationCode) {
 		return
 	}
 
@@ -235,26 +219,8 @@ func (userApi *UserApi) ForgotPassword(c *gin.Context) {
 		return
 	}
 
-	session := sessions.Default(c)
-
-	// 两次邮箱一致性判断
-	savedEmail := session.Get("email")
-	if savedEmail == nil || savedEmail.(string) != req.Email {
-		response.FailWithMessage("This email doesn't match the email to be verified", c)
-		return
-	}
-
-	// 获取会话中存储的邮箱验证码
-	savedCode := session.Get("verification_code")
-	if savedCode == nil || savedCode.(string) != req.VerificationCode {
-		response.FailWithMessage("Invalid verification code", c)
-		return
-	}
-
-	// 判断邮箱验证码是否过期
-	savedTime := session.Get("expire_time")
-	if savedTime.(int64) < time.Now().Unix() {
-		response.FailWithMessage("The verification code has expired, please resend it", c)
+	// 校验并消费找回密码验证码
+	if !verifyEmailCode(c, req.Email, appTypes.VerificationForgotPassword, req.VerificationCode) {
 		return
 	}
 
@@ -339,6 +305,34 @@ func (userApi *UserApi) UserChangeInfo(c *gin.Context) {
 	response.OkWithMessage("Successfully changed user information", c)
 }
 
+// UserChangeEmail 修改邮箱，需要先向新邮箱发送验证码
+func (userApi *UserApi) UserChangeEmail(c *gin.Context) {
+	var req request.UserChangeEmail
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		response.FailWithMessage(err.Error(), c)
+		return
+	}
+
+	// 校验并消费修改邮箱验证码
+	if !verifyEmailCode(c, req.Email, appTypes.VerificationChangeEmail, req.VerificationCode) {
+		return
+	}
+
+	req.UserID = utils.GetUserID(c)
+	err = userService.UserChangeEmail(req)
+	if errors.Is(err, service.ErrPasswordMismatch) {
+		response.FailWithMessage("Failed to change email, password does not match the current account", c)
+		return
+	}
+	if err != nil {
+		global.Log.Error("Failed to change email:", zap.Error(err))
+		response.FailWithMessage("Failed to change email", c)
+		return
+	}
+	response.OkWithMessage("Successfully changed email", c)
+}
+
 // UserWeather 获取天气
 func (userApi *UserApi) UserWeather(c *gin.Context) {
 	ip := c.ClientIP()
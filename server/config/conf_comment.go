@@ -0,0 +1,10 @@
+package config
+
+import "server/model/appTypes"
+
+// Comment 评论配置
+type Comment struct {
+	Moderation appTypes.CommentModeration `json:"moderation" yaml:"moderation" binding:"required,oneof=off all first"` // 审核模式，off 不审核，all 审核所有评论，first 只审核首次评论的用户
+	EditWindow int                        `json:"edit_window" yaml:"edit_window" binding:"min=0"`                      // 发布后允许编辑的时间，单位为分钟，0 表示不限制
+	MaxDepth   int                        `json:"max_depth" yaml:"max_depth" binding:"min=0,ne=1"`                     // 评论树的最大层级（包含一级评论），更深的回复展开在最深一层，0 表示不限制
+}
@@ -6,4 +6,12 @@ type ES struct {
 	Username       string `json:"username" yaml:"username"`                 // 用于连接 Elasticsearch 的用户名
 	Password       string `json:"password" yaml:"password"`                 // 用于连接 Elasticsearch 的密码
 	IsConsolePrint bool   `json:"is_console_print" yaml:"is_console_print"` // 是否在控制台打印 Elasticsearch 语句，true 表示打印，false 表示不打印
+
+	Analyzers map[string]ESAnalyzer `json:"analyzers" yaml:"analyzers"` // 文章文本字段的分词器，键为字段名 title、abstract、content，未配置的字段使用 ES 默认分词器
+}
+
+// ESAnalyzer 文本字段的分词器配置
+type ESAnalyzer struct {
+	Index  string `json:"index" yaml:"index"`   // 建立索引时使用的分词器，例如 ik_max_word
+	Search string `json:"search" yaml:"search"` // 搜索时使用的分词器，例如 ik_smart，为空时与 index 相同
 }
\ No newline at end of file
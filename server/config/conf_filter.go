@@ -0,0 +1,25 @@
+package config
+
+import (
+	"server/model/appTypes"
+	"time"
+)
+
+// Filter 评论和反馈的内容过滤配置，敏感词在后台单独管理
+type Filter struct {
+	MaxLinks        int                   `json:"max_links" yaml:"max_links" binding:"min=0"`                                        // 允许包含的最大链接数
+	LinkAction      appTypes.FilterAction `json:"link_action" yaml:"link_action" binding:"required,oneof=mask moderate reject"`      // 链接过多时的处理方式
+	DuplicateWindow int                   `json:"duplicate_window" yaml:"duplicate_window" binding:"min=0"`                          // 重复内容检查的时间窗口，单位为秒，0 表示不检查
+	DuplicateAction appTypes.FilterAction `json:"duplicate_action" yaml:"duplicate_action" binding:"required,oneof=moderate reject"` // 重复发布时的处理方式
+	RateLimit       int                   `json:"rate_limit" yaml:"rate_limit" binding:"min=0"`                                      // 时间窗口内允许发布的次数，0 表示不限制
+	RateWindow      int                   `json:"rate_window" yaml:"rate_window" binding:"min=0"`                                    // 发布频率的时间窗口，单位为秒
+	RateAction      appTypes.FilterAction `json:"rate_action" yaml:"rate_action" binding:"required,oneof=moderate reject"`           // 发布过于频繁时的处理方式
+}
+
+func (f Filter) DuplicateDuration() time.Duration {
+	return time.Duration(f.DuplicateWindow) * time.Second
+}
+
+func (f Filter) RateDuration() time.Duration {
+	return time.Duration(f.RateWindow) * time.Second
+}
@@ -0,0 +1,9 @@
+package config
+
+// Newsletter 邮件订阅配置
+type Newsletter struct {
+	Secret       string `json:"secret" yaml:"secret" binding:"required"`      // 订阅确认和退订链接的签名密钥，为空时启动时生成，修改后已发出的链接失效
+	OnPublish    bool   `json:"on_publish" yaml:"on_publish"`                 // 发布文章时是否通知订阅者
+	WeeklyDigest bool   `json:"weekly_digest" yaml:"weekly_digest"`           // 是否每周发送文章摘要
+	BatchSize    int    `json:"batch_size" yaml:"batch_size" binding:"min=1"` // 每分钟最多处理的订阅者数
+}
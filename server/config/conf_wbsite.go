@@ -8,6 +8,7 @@ type Website struct {
 	Slogan               string `json:"slogan" yaml:"slogan"`                                 // 网站标语
 	SloganEn             string `json:"slogan_en" yaml:"slogan_en"`                           // 英文标语
 	Description          string `json:"description" yaml:"description"`                       // 网站描述
+	SiteURL              string `json:"site_url" yaml:"site_url"`                             // 网站地址，用于生成订阅源等处的绝对链接，例如 https://blog.example.com
 	Version              string `json:"version" yaml:"version"`                               // 网站版本
 	CreatedAt            string `json:"created_at" yaml:"created_at"`                         // 创建时间
 	IcpFiling            string `json:"icp_filing" yaml:"icp_filing"`                         // ICP 备案
@@ -21,4 +22,5 @@ type Website struct {
 	Email                string `json:"email" yaml:"email"`                                   // 邮箱
 	QQImage              string `json:"qq_image" yaml:"qq_image"`                             // QQ 图片链接
 	WechatImage          string `json:"wechat_image" yaml:"wechat_image"`                     // 微信图片链接
+	Robots               string `json:"robots" yaml:"robots"`                                 // robots.txt 的内容，为空时允许抓取全部页面并指向站点地图
 }
\ No newline at end of file
@@ -42,6 +42,26 @@ var (
 		Name:  "es-import",
 		Usage: "Imports data into Elasticsearch from a specified file.",
 	}
+	// 用于重建 Elasticsearch 索引的布尔标志，重建过程中不影响线上读写
+	esReindexFlag = &cli.BoolFlag{
+		Name:  "es-reindex",
+		Usage: "Rebuilds the Elasticsearch index with the current mapping without downtime.",
+	}
+	// 用于将 Elasticsearch 索引回滚到上一个版本的布尔标志
+	esRollbackFlag = &cli.BoolFlag{
+		Name:  "es-rollback",
+		Usage: "Rolls the Elasticsearch index back to the previous version.",
+	}
+	// 用于校对收藏数、评论数以及类别和标签文章数的布尔标志，只报告偏差
+	reconcileFlag = &cli.BoolFlag{
+		Name:  "reconcile",
+		Usage: "Recomputes likes, comments, category and tag counters and reports any drift.",
+	}
+	// 用于校对并修正计数偏差的布尔标志
+	reconcileFixFlag = &cli.BoolFlag{
+		Name:  "reconcile-fix",
+		Usage: "Recomputes likes, comments, category and tag counters and fixes any drift.",
+	}
 	// 用于根据 config.yaml 文件中指定的名称、电子邮件和地址创建管理员的布尔标志
 	adminFlag = &cli.BoolFlag{
 		Name:  "admin",
@@ -125,6 +145,37 @@ func Run(c *cli.Context) {
 			// 如果操作成功，记录成功日志并显示导入的记录数量
 			global.Log.Info(fmt.Sprintf("Successfully imported ES data, totaling %d records", num))
 		}
+	case c.Bool(esReindexFlag.Name):
+		// 如果 es-reindex 标志被设置，执行 Elasticsearch 索引重建操作
+		if index, num, err := ElasticsearchReindex(); err != nil {
+			// 如果操作失败，记录错误日志
+			global.Log.Error("Failed to reindex ES data:", zap.Error(err))
+		} else {
+			// 如果操作成功，记录成功日志并显示新索引及记录数量
+			global.Log.Info(fmt.Sprintf("Successfully reindexed ES data into %s, totaling %d records", index, num))
+		}
+	case c.Bool(esRollbackFlag.Name):
+		// 如果 es-rollback 标志被设置，执行 Elasticsearch 索引回滚操作
+		if index, err := ElasticsearchRollback(); err != nil {
+			// 如果操作失败，记录错误日志
+			global.Log.Error("Failed to roll back ES index:", zap.Error(err))
+		} else {
+			// 如果操作成功，记录成功日志
+			global.Log.Info("Successfully rolled back ES index to " + index)
+		}
+	case c.Bool(reconcileFlag.Name), c.Bool(reconcileFixFlag.Name):
+		// 如果 reconcile 或 reconcile-fix 标志被设置，执行计数校对操作
+		fix := c.Bool(reconcileFixFlag.Name)
+		if num, err := Reconcile(fix); err != nil {
+			// 如果操作失败，记录错误日志
+			global.Log.Error("Failed to reconcile counters:", zap.Error(err))
+		} else if fix {
+			// 如果操作成功，记录成功日志并显示修正的偏差数量
+			global.Log.Info(fmt.Sprintf("Successfully reconciled counters, %d drifts fixed", num))
+		} else {
+			// 如果操作成功，记录成功日志并显示发现的偏差数量
+			global.Log.Info(fmt.Sprintf("Successfully reconciled counters, %d drifts found", num))
+		}
 	case c.Bool(adminFlag.Name):
 		// 如果 admin 标志被设置，执行创建管理员的操作
 		if err := Admin(); err != nil {
@@ -156,6 +207,10 @@ func NewApp() *cli.App {
 		esFlag,
 		esExportFlag,
 		esImportFlag,
+		esReindexFlag,
+		esRollbackFlag,
+		reconcileFlag,
+		reconcileFixFlag,
 		adminFlag,
 	}
 	// 设置应用程序的默认操作，即当没有指定具体子命令时执行的操作
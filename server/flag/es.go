@@ -3,6 +3,7 @@ package flag
 import (
 	"bufio"
 	"fmt"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
 	"os"
 	"server/model/elasticsearch"
 	"server/service"
@@ -21,6 +22,7 @@ func Elasticsearch() error {
 	if indexExists {
 		// 打印提示信息
 		fmt.Println("The index already exists. Do you want to delete the data and recreate the index? (y/n)")
+		fmt.Println("Enter 'm' to migrate the mapping of the existing index and keep the data.")
 
 		// 读取用户输入
 		scanner := bufio.NewScanner(os.Stdin)
@@ -31,20 +33,54 @@ func Elasticsearch() error {
 		case "y":
 			// 如果用户输入 y，删除索引
 			fmt.Println("Proceeding to delete the data and recreate the index...")
-			if err := esService.IndexDelete(elasticsearch.ArticleIndex()); err != nil {
+			if err := esService.ArticleIndexDelete(); err != nil {
 				return err
 			}
+		case "m":
+			// 如果用户输入 m，在保留数据的前提下迁移映射
+			fmt.Println("Proceeding to migrate the index mapping...")
+			return ElasticsearchMigrate()
 		case "n":
 			// 如果用户输入 n，退出程序
 			fmt.Println("Exiting the program.")
 			os.Exit(0)
 		default:
 			// 如果用户输入无效，提示重新输入
-			fmt.Println("Invalid input. Please enter 'y' to delete and recreate the index, or 'n' to exit.")
+			fmt.Println("Invalid input. Please enter 'y' to delete and recreate the index, 'm' to migrate the mapping, or 'n' to exit.")
 			return Elasticsearch() // 递归调用，重新输入
 		}
 	}
 
 	// 创建索引
-	return esService.IndexCreate(elasticsearch.ArticleIndex(), elasticsearch.ArticleMapping())
+	return esService.ArticleIndexCreate()
+}
+
+// ElasticsearchMigrate 为已存在的 ES 索引添加新的字段映射，并重新索引已有文档使其生效
+func ElasticsearchMigrate() error {
+	esService := service.ServiceGroupApp.EsService
+
+	// 标题的自动补全子字段，以及别名和发布状态字段，别名和状态需要按关键词精确查询，发布时间需要按日期排序
+	// 已有文档中的这些字段已被自动映射为其他类型时无法修改，需要重建索引
+	mapping := elasticsearch.ArticleMapping().Properties
+	properties := make(map[string]types.Property)
+	for _, field := range []string{"title", "slug", "status", "publish_at"} {
+		properties[field] = mapping[field]
+	}
+	if err := esService.IndexPutMapping(elasticsearch.ArticleIndex(), properties); err != nil {
+		return err
+	}
+
+	num, err := esService.IndexUpdateDocs(elasticsearch.ArticleIndex())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Successfully migrated the index mapping, %d documents updated\n", num)
+
+	// 把已有文章的别名写入别名表，别名的唯一性以别名表为准
+	num, err = service.ServiceGroupApp.ArticleService.SyncSlugs()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Successfully synchronized article slugs, %d slugs added\n", num)
+	return nil
 }
\ No newline at end of file
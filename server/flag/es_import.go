@@ -35,11 +35,11 @@ func ElasticsearchImport(jsonPath string) (int, error) {
 		return 0, err
 	}
 	if indexExists {
-		if err := esService.IndexDelete(elasticsearch.ArticleIndex()); err != nil {
+		if err := esService.ArticleIndexDelete(); err != nil {
 			return 0, err
 		}
 	}
-	err = esService.IndexCreate(elasticsearch.ArticleIndex(), elasticsearch.ArticleMapping())
+	err = esService.ArticleIndexCreate()
 	if err != nil {
 		return 0, err
 	}
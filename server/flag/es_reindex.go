@@ -0,0 +1,15 @@
+package flag
+
+import (
+	"server/service"
+)
+
+// ElasticsearchReindex 使用当前的映射重建 ES 索引，不影响线上读写，返回新索引的名称和文档数
+func ElasticsearchReindex() (string, int64, error) {
+	return service.ServiceGroupApp.EsService.ArticleReindex()
+}
+
+// ElasticsearchRollback 将 ES 索引回滚到上一个版本，返回回滚后的索引名称
+func ElasticsearchRollback() (string, error) {
+	return service.ServiceGroupApp.EsService.ArticleIndexRollback()
+}
@@ -0,0 +1,18 @@
+package flag
+
+import (
+	"fmt"
+	"server/service"
+)
+
+// Reconcile 重新计算收藏数、评论数以及类别和标签的文章数并打印偏差，fix 为 true 时同时修正，返回偏差数量
+func Reconcile(fix bool) (int, error) {
+	drifts, err := service.ServiceGroupApp.ReconcileService.Reconcile(fix)
+	if err != nil {
+		return 0, err
+	}
+	for _, d := range drifts {
+		fmt.Printf("%-8s %-40s recorded: %-6d actual: %d\n", d.Kind, d.Key, d.Recorded, d.Actual)
+	}
+	return len(drifts), nil
+}
@@ -11,8 +11,28 @@ func SQL() error {
 		&database.Advertisement{},
 		&database.ArticleCategory{},
 		&database.ArticleLike{},
+		&database.ArticleRevision{},
+		&database.ArticleSlug{},
+		&database.ArticleSlugRedirect{},
 		&database.ArticleTag{},
+		&database.ArticleViewDaily{},
+		&database.ArticleViewFlush{},
+		&database.ArticleVisit{},
+		&database.ArticleStatDaily{},
+		&database.ArticleReferrerDaily{},
+		&database.TrafficDaily{},
+		&database.SensitiveWord{},
+		&database.FilterLog{},
+		&database.Notification{},
+		&database.NotificationPreference{},
+		&database.MailTemplate{},
+		&database.MailLog{},
+		&database.Subscriber{},
+		&database.NewsletterIssue{},
 		&database.Comment{},
+		&database.CommentReaction{},
+		&database.CommentEdit{},
+		&database.EsOutbox{},
 		&database.Feedback{},
 		&database.FooterLink{},
 		&database.FriendLink{},
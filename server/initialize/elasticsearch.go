@@ -1,11 +1,13 @@
 package initialize
 
 import (
+	"errors"
 	"github.com/elastic/elastic-transport-go/v8/elastictransport"
 	"github.com/elastic/go-elasticsearch/v8"
 	"go.uber.org/zap"
 	"os"
 	"server/global"
+	"server/service"
 )
 
 // ConnectEs 初始化并返回一个配置好的 Elasticsearch 客户端
@@ -35,3 +37,25 @@ func ConnectEs() *elasticsearch.TypedClient {
 
 	return client
 }
+
+// InitEsAnalyzers 检查配置的分词器是否可用，需要在生成索引映射之前调用
+func InitEsAnalyzers() {
+	service.ServiceGroupApp.EsService.CheckAnalyzers()
+}
+
+// SyncEsAnalyzers 分词器配置发生变化时重建文章索引，使已有文章按新的分词器重新分词
+// 多个实例同时启动时只有获取到锁的实例会重建索引
+func SyncEsAnalyzers() {
+	index, total, err := service.ServiceGroupApp.EsService.ArticleReanalyze()
+	if errors.Is(err, service.ErrArticleReindexRunning) {
+		global.Log.Info("Article index is being rebuilt by another instance, skipping reanalysis")
+		return
+	}
+	if err != nil {
+		global.Log.Error("Failed to reanalyze articles:", zap.Error(err))
+		return
+	}
+	if index != "" {
+		global.Log.Info("Successfully reanalyzed articles", zap.String("index", index), zap.Int64("total", total))
+	}
+}
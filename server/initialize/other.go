@@ -24,6 +24,16 @@ func OtherInit() {
 		os.Exit(1)
 	}
 
+	// 未配置订阅链接的签名密钥时生成随机密钥并写回配置文件，不能使用公开的默认密钥，否则任何人都能伪造确认和退订链接
+	if global.Config.Newsletter.Secret == "" {
+		global.Config.Newsletter.Secret = utils.GenerateSecret(32)
+		if err := utils.SaveYAML(); err != nil {
+			global.Log.Error("Failed to save the generated newsletter secret:", zap.Error(err))
+			os.Exit(1)
+		}
+		global.Log.Info("Generated a random newsletter secret")
+	}
+
 	// 配置本地缓存过期时间（使用刷新令牌过期时间，方便在远程登录或账户冻结时对 JWT 进行黑名单处理）
 	global.BlackCache = local_cache.NewCache(
 		local_cache.SetDefaultExpire(refreshTokenExpiry),
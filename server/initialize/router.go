@@ -4,7 +4,9 @@ import (
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-contrib/sessions/cookie"
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 	"net/http"
+	"os"
 	"server/global"
 	"server/middleware"
 	"server/router"
@@ -19,6 +21,11 @@ func InitRouter() *gin.Engine {
 	gin.SetMode(global.Config.System.Env)
 	// 创建 Gin 引擎
 	Router := gin.Default()
+	// 只信任配置的反向代理转发的客户端 IP，否则任何人都可以通过 X-Forwarded-For 伪造 IP 绕过按 IP 的限制
+	if err := Router.SetTrustedProxies(global.Config.System.TrustedProxies); err != nil {
+		global.Log.Error("Invalid trusted proxies:", zap.Error(err))
+		os.Exit(1)
+	}
 	// 使用日志记录中间件
 	//	true 可能是一个选项，表示是否在 panic 发生时返回 JSON 格式错误信息
 	Router.Use(middleware.GinLogger(), middleware.GinRecovery(true))
@@ -35,10 +42,13 @@ func InitRouter() *gin.Engine {
 	routerGroup := router.RouterGroupApp
 
 	publicGroup := Router.Group(global.Config.System.RouterPrefix)
+	// 只统计前台公开接口的流量
+	publicGroup.Use(middleware.TrafficRecord())
 	privateGroup := Router.Group(global.Config.System.RouterPrefix)
 	privateGroup.Use(middleware.JWTAuth())
 	adminGroup := Router.Group(global.Config.System.RouterPrefix)
 	adminGroup.Use(middleware.JWTAuth()).Use(middleware.AdminAuth())
+	rootGroup := Router.Group("")
 	// 后面设置谁可以访问
 	{
 		routerGroup.InitBaseRouter(publicGroup)
@@ -55,6 +65,16 @@ func InitRouter() *gin.Engine {
 		routerGroup.InitFriendLinkRouter(adminGroup, publicGroup)
 		routerGroup.InitWebsiteRouter(adminGroup, publicGroup)
 		routerGroup.InitConfigRouter(adminGroup)
+		routerGroup.InitOutboxRouter(adminGroup)
+		routerGroup.InitStatsRouter(adminGroup)
+		routerGroup.InitTrafficRouter(adminGroup)
+		routerGroup.InitContentFilterRouter(adminGroup)
+		routerGroup.InitMailRouter(adminGroup)
+		routerGroup.InitNewsletterRouter(adminGroup, publicGroup)
+		routerGroup.InitNotificationRouter(privateGroup)
+		routerGroup.InitStreamRouter(privateGroup)
+		routerGroup.InitFeedRouter(publicGroup)
+		routerGroup.InitSitemapRouter(rootGroup)
 	}
 	return Router
 }
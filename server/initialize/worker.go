@@ -0,0 +1,13 @@
+package initialize
+
+import (
+	"server/service"
+)
+
+// InitWorkers 启动常驻的后台协程
+func InitWorkers() {
+	// 在请求之外投递 ES 发件箱中的事件
+	go service.ServiceGroupApp.OutboxService.Run()
+	// 订阅实时推送的频道并分发给本实例的连接
+	service.ServiceGroupApp.StreamService.Start()
+}
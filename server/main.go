@@ -14,12 +14,17 @@ func main() {
 	global.DB = initialize.InitGorm()
 	global.Redis = initialize.ConnectRedis()
 	global.ESClient = initialize.ConnectEs()
+	initialize.InitEsAnalyzers()
 
 	defer global.Redis.Close()
 
 	flag.InitFlag()
 
+	initialize.SyncEsAnalyzers()
+
 	initialize.InitCron()
 
+	initialize.InitWorkers()
+
 	core.RunServer()
 }
\ No newline at end of file
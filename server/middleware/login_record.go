@@ -7,6 +7,7 @@ import (
 	"server/global"
 	"server/model/database"
 	"server/service"
+	"sync"
 )
 
 // LoginRecord 是一个中间件，用于记录登录日志
@@ -68,14 +69,16 @@ func getAddressFromIP(ip string, gaodeService service.GaodeService) string {
 	return res.Province
 }
 
+// uaParser 解析器初始化时需要编译大量正则，只创建一次
+var uaParser = sync.OnceValue(uaparser.NewFromSaved)
+
 // 解析用户代理（User-Agent）字符串，提取操作系统、设备信息和浏览器信息
 func parseUserAgent(userAgent string) (os, deviceInfo, browserInfo string) {
 	os = userAgent
 	deviceInfo = userAgent
 	browserInfo = userAgent
 
-	parser := uaparser.NewFromSaved()
-	cli := parser.Parse(userAgent)
+	cli := uaParser().Parse(userAgent)
 	os = cli.Os.Family
 	deviceInfo = cli.Device.Family
 	browserInfo = cli.UserAgent.Family
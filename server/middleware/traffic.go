@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"server/global"
+	"server/model/other"
+	"server/service"
+	"server/utils"
+	"sync"
+	"time"
+)
+
+const (
+	trafficQueueSize = 1024 // 等待记录的请求数，队列已满时丢弃新的记录
+	trafficWorkers   = 4    // 记录流量的协程数
+)
+
+// trafficTask 等待记录的一次请求
+type trafficTask struct {
+	record    other.TrafficRecord
+	ip        string
+	userAgent string
+}
+
+var trafficQueue = make(chan trafficTask, trafficQueueSize)
+
+var startTraffic sync.Once
+
+// TrafficRecord 是一个中间件，用于记录站点的访问流量，只统计 GET 请求，路径按路由模板记录
+// 只应挂在前台公开的路由组上，后台管理、静态文件和实时推送的请求不计入流量
+func TrafficRecord() gin.HandlerFunc {
+	startTraffic.Do(func() {
+		for i := 0; i < trafficWorkers; i++ {
+			go recordTraffic()
+		}
+	})
+
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		if c.Request.Method != http.MethodGet || c.FullPath() == "" {
+			return
+		}
+
+		// 请求结束后上下文会被复用，需要先取出用到的数据
+		task := trafficTask{
+			record: other.TrafficRecord{
+				Path:     c.FullPath(),
+				Referrer: utils.ReferrerHost(c.Request.Referer()),
+				Duration: time.Since(start),
+			},
+			ip:        c.ClientIP(),
+			userAgent: c.Request.UserAgent(),
+		}
+
+		// 交给后台协程异步记录，记录跟不上时丢弃，不阻塞请求
+		select {
+		case trafficQueue <- task:
+		default:
+		}
+	}
+}
+
+// recordTraffic 从队列中取出请求并记录流量
+func recordTraffic() {
+	trafficService := service.ServiceGroupApp.TrafficService
+	for task := range trafficQueue {
+		record := task.record
+		record.Country, record.Province = trafficService.Region(task.ip)
+		record.OS, record.Device, record.Browser = parseUserAgent(task.userAgent)
+		if err := trafficService.Record(record); err != nil {
+			global.Log.Error("Failed to record traffic", zap.Error(err))
+		}
+	}
+}
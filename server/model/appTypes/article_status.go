@@ -0,0 +1,11 @@
+package appTypes
+
+// ArticleStatus 文章状态
+type ArticleStatus string
+
+const (
+	Draft     ArticleStatus = "draft"     // 草稿
+	Scheduled ArticleStatus = "scheduled" // 定时发布
+	Published ArticleStatus = "published" // 已发布
+	Archived  ArticleStatus = "archived"  // 已归档
+)
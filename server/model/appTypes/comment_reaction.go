@@ -0,0 +1,22 @@
+package appTypes
+
+// CommentReaction 评论回应类型
+type CommentReaction string
+
+const (
+	ReactionLike     CommentReaction = "like"     // 点赞
+	ReactionHeart    CommentReaction = "heart"    // 喜爱
+	ReactionLaugh    CommentReaction = "laugh"    // 大笑
+	ReactionHooray   CommentReaction = "hooray"   // 欢呼
+	ReactionConfused CommentReaction = "confused" // 疑惑
+	ReactionRocket   CommentReaction = "rocket"   // 火箭
+)
+
+// CommentSort 一级评论的排序方式
+type CommentSort string
+
+const (
+	CommentOldest    CommentSort = "oldest"     // 最早发布
+	CommentNewest    CommentSort = "newest"     // 最新发布
+	CommentMostLiked CommentSort = "most_liked" // 点赞最多
+)
@@ -0,0 +1,20 @@
+package appTypes
+
+// CommentStatus 评论审核状态，只有审核通过的评论对外展示并计入文章评论数
+type CommentStatus string
+
+const (
+	CommentPending  CommentStatus = "pending"  // 等待审核
+	CommentApproved CommentStatus = "approved" // 审核通过
+	CommentRejected CommentStatus = "rejected" // 审核未通过
+	CommentSpam     CommentStatus = "spam"     // 垃圾评论
+)
+
+// CommentModeration 评论审核模式
+type CommentModeration string
+
+const (
+	ModerationOff   CommentModeration = "off"   // 不需要审核
+	ModerationAll   CommentModeration = "all"   // 所有评论都需要审核
+	ModerationFirst CommentModeration = "first" // 只有首次评论需要审核
+)
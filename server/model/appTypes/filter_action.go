@@ -0,0 +1,11 @@
+package appTypes
+
+// FilterAction 内容过滤规则命中后的处理方式
+type FilterAction string
+
+const (
+	FilterPass     FilterAction = "pass"     // 放行
+	FilterMask     FilterAction = "mask"     // 将命中的内容替换为 ***
+	FilterModerate FilterAction = "moderate" // 转入人工审核
+	FilterReject   FilterAction = "reject"   // 拒绝发布
+)
@@ -0,0 +1,10 @@
+package appTypes
+
+// MailStatus 邮件投递状态
+type MailStatus string
+
+const (
+	MailSent   MailStatus = "sent"   // 发送成功
+	MailFailed MailStatus = "failed" // 发送失败，等待重试
+	MailDead   MailStatus = "dead"   // 多次重试后仍然失败，已移入死信队列
+)
@@ -0,0 +1,19 @@
+package appTypes
+
+// NotificationType 通知类型
+type NotificationType string
+
+const (
+	NotificationCommentReply  NotificationType = "comment_reply"  // 评论被回复
+	NotificationMention       NotificationType = "mention"        // 在评论中被 @
+	NotificationFeedbackReply NotificationType = "feedback_reply" // 反馈被回复
+	NotificationCommentLike   NotificationType = "comment_like"   // 评论被点赞
+)
+
+// NotificationTypes 所有通知类型
+var NotificationTypes = []NotificationType{
+	NotificationCommentReply,
+	NotificationMention,
+	NotificationFeedbackReply,
+	NotificationCommentLike,
+}
@@ -0,0 +1,19 @@
+package appTypes
+
+// OutboxOperation 发件箱中的 ES 操作类型
+type OutboxOperation string
+
+const (
+	OutboxIndex  OutboxOperation = "index"  // 索引文档
+	OutboxUpdate OutboxOperation = "update" // 局部更新文档
+	OutboxScript OutboxOperation = "script" // 使用 painless 脚本更新文档
+	OutboxDelete OutboxOperation = "delete" // 删除文档
+)
+
+// OutboxStatus 发件箱事件状态，投递成功的事件会被删除
+type OutboxStatus string
+
+const (
+	OutboxPending OutboxStatus = "pending" // 等待投递
+	OutboxFailed  OutboxStatus = "failed"  // 多次重试后仍然失败，需要人工处理
+)
@@ -0,0 +1,11 @@
+package appTypes
+
+// StreamEvent 实时推送的事件类型
+type StreamEvent string
+
+const (
+	StreamComment      StreamEvent = "comment"      // 文章的新评论
+	StreamNotification StreamEvent = "notification" // 个人通知
+	StreamFeedback     StreamEvent = "feedback"     // 新反馈，仅推送给管理员
+	StreamRegister     StreamEvent = "register"     // 新用户注册，仅推送给管理员
+)
@@ -0,0 +1,14 @@
+package appTypes
+
+// TrafficDimension 流量统计的维度
+type TrafficDimension string
+
+const (
+	TrafficPath     TrafficDimension = "path"     // 访问路径
+	TrafficReferrer TrafficDimension = "referrer" // 来源域名
+	TrafficCountry  TrafficDimension = "country"  // 访客所在国家，只区分国内和境外
+	TrafficProvince TrafficDimension = "province" // 访客所在省份
+	TrafficDevice   TrafficDimension = "device"   // 设备
+	TrafficOS       TrafficDimension = "os"       // 操作系统
+	TrafficBrowser  TrafficDimension = "browser"  // 浏览器
+)
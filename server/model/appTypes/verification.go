@@ -0,0 +1,10 @@
+package appTypes
+
+// VerificationPurpose 邮箱验证码的用途，不同用途的验证码互不通用
+type VerificationPurpose string
+
+const (
+	VerificationRegister       VerificationPurpose = "register"        // 注册
+	VerificationForgotPassword VerificationPurpose = "forgot_password" // 找回密码
+	VerificationChangeEmail    VerificationPurpose = "change_email"    // 修改邮箱
+)
@@ -0,0 +1,17 @@
+package database
+
+import "server/global"
+
+// ArticleRevision 文章修订记录表，每次创建或更新文章时保存一份完整快照
+type ArticleRevision struct {
+	global.MODEL
+	ArticleID string   `json:"article_id" gorm:"size:64;index"`       // 文章 ID
+	Title     string   `json:"title"`                                 // 文章标题
+	Abstract  string   `json:"abstract" gorm:"type:text"`             // 文章简介
+	Content   string   `json:"content" gorm:"type:longtext"`          // 文章内容
+	Tags      []string `json:"tags" gorm:"type:text;serializer:json"` // 文章标签
+	Category  string   `json:"category"`                              // 文章类别
+	Cover     string   `json:"cover"`                                 // 文章封面
+	EditorID  uint     `json:"editor_id"`                             // 编辑者 ID
+	Editor    User     `json:"-" gorm:"foreignKey:EditorID"`
+}
@@ -0,0 +1,10 @@
+package database
+
+import "time"
+
+// ArticleSlug 文章当前别名表，别名的唯一性以该表为准，ES 中的文档由发件箱异步更新，不能用来检查别名是否冲突
+type ArticleSlug struct {
+	Slug      string    `json:"slug" gorm:"primaryKey;size:191"`       // 当前别名
+	ArticleID string    `json:"article_id" gorm:"size:64;uniqueIndex"` // 文章 ID
+	CreatedAt time.Time `json:"created_at"`                            // 创建时间
+}
@@ -0,0 +1,10 @@
+package database
+
+import "time"
+
+// ArticleSlugRedirect 文章旧别名表，文章修改别名后旧链接依然可以跳转到该文章
+type ArticleSlugRedirect struct {
+	Slug      string    `json:"slug" gorm:"primaryKey;size:191"` // 旧别名
+	ArticleID string    `json:"article_id" gorm:"size:64;index"` // 文章 ID
+	CreatedAt time.Time `json:"created_at"`                      // 创建时间
+}
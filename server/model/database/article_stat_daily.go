@@ -0,0 +1,19 @@
+package database
+
+// ArticleStatDaily 文章每日统计表，由访问事件、每日浏览量、收藏和评论汇总而来
+type ArticleStatDaily struct {
+	ArticleID string `json:"article_id" gorm:"size:64;primaryKey"` // 文章 ID
+	Date      string `json:"date" gorm:"type:date;primaryKey"`     // 日期，格式为 2006-01-02
+	Views     int    `json:"views"`                                // 浏览量
+	Visitors  int    `json:"visitors"`                             // 独立访客数
+	Likes     int    `json:"likes"`                                // 新增收藏数
+	Comments  int    `json:"comments"`                             // 新增评论数
+}
+
+// ArticleReferrerDaily 文章每日来源统计表
+type ArticleReferrerDaily struct {
+	ArticleID string `json:"article_id" gorm:"size:64;primaryKey"` // 文章 ID
+	Date      string `json:"date" gorm:"type:date;primaryKey"`     // 日期，格式为 2006-01-02
+	Referrer  string `json:"referrer" gorm:"size:255;primaryKey"`  // 来源域名，直接访问为空
+	Visits    int    `json:"visits"`                               // 访问次数
+}
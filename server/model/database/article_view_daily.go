@@ -0,0 +1,16 @@
+package database
+
+import "time"
+
+// ArticleViewDaily 文章每日浏览量表
+type ArticleViewDaily struct {
+	ArticleID string `json:"article_id" gorm:"size:64;primaryKey"` // 文章 ID
+	Date      string `json:"date" gorm:"type:date;primaryKey"`     // 日期，格式为 2006-01-02
+	Views     int    `json:"views"`                                // 浏览量
+}
+
+// ArticleViewFlush 已经写入每日浏览量表的浏览量批次，同一批次重试时不会重复累加
+type ArticleViewFlush struct {
+	Batch     string    `json:"batch" gorm:"size:36;primaryKey"` // 批次 ID
+	CreatedAt time.Time `json:"created_at" gorm:"index"`         // 写入时间，过期的记录会被清理
+}
@@ -0,0 +1,12 @@
+package database
+
+import "time"
+
+// ArticleVisit 文章访问事件表，每次计入浏览量的访问记录一条，用于统计独立访客和来源
+type ArticleVisit struct {
+	ID        uint      `json:"id" gorm:"primarykey"`            // 主键 ID
+	ArticleID string    `json:"article_id" gorm:"size:64;index"` // 文章 ID
+	VisitorID string    `json:"visitor_id" gorm:"size:64"`       // 访客标识
+	Referrer  string    `json:"referrer" gorm:"size:255"`        // 来源域名，直接访问为空
+	CreatedAt time.Time `json:"created_at" gorm:"index"`         // 访问时间
+}
@@ -1,43 +1,62 @@
 package database
 
 import (
-	"context"
-	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
-	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/scriptlanguage"
 	"github.com/gofrs/uuid"
 	"gorm.io/gorm"
 	"server/global"
+	"server/model/appTypes"
 	"server/model/elasticsearch"
+	"time"
 )
 
 // Comment 评论表
 type Comment struct {
 	global.MODEL
-	ArticleID string    `json:"article_id"` // 文章 ID
-	PID       *uint     `json:"p_id"`       // 父评论 ID
-	PComment  *Comment  `json:"-" gorm:"foreignKey:PID"`
-	Children  []Comment `json:"children" gorm:"foreignKey:PID"`                  // 子评论
-	UserUUID  uuid.UUID `json:"user_uuid" gorm:"type:char(36)"`                  // 用户 uuid
-	User      User      `json:"user" gorm:"foreignKey:UserUUID;references:UUID"` // 关联的用户
-	Content   string    `json:"content"`                                         // 内容
+	ArticleID string                 `json:"article_id"`        // 文章 ID
+	PID       *uint                  `json:"p_id" gorm:"index"` // 父评论 ID，递归查询回复时使用
+	PComment  *Comment               `json:"-" gorm:"foreignKey:PID"`
+	Children  []Comment              `json:"children" gorm:"foreignKey:PID"`                  // 子评论
+	UserUUID  uuid.UUID              `json:"user_uuid" gorm:"type:char(36)"`                  // 用户 uuid
+	User      User                   `json:"user" gorm:"foreignKey:UserUUID;references:UUID"` // 关联的用户
+	Content   string                 `json:"content"`                                         // 内容
+	Status    appTypes.CommentStatus `json:"status" gorm:"size:16;default:approved;index"`    // 审核状态
+	Likes     int                    `json:"likes" gorm:"default:0"`                          // 点赞数
+	Pinned    bool                   `json:"pinned" gorm:"default:false"`                     // 是否置顶，只有一级评论可以置顶
+	Deleted   bool                   `json:"deleted" gorm:"default:false"`                    // 是否已删除，有回复的评论删除后保留为占位
+	EditedAt  *time.Time             `json:"edited_at"`                                       // 最后编辑时间
+	Notified  bool                   `json:"-" gorm:"default:false"`                          // 是否已发送过回复和提及通知，编辑后重新审核通过时不再发送
+
+	Reactions map[appTypes.CommentReaction]int `json:"reactions" gorm:"-"` // 各类回应的数量
 }
 
-// AfterCreate 钩子，创建后调用
-func (c *Comment) AfterCreate(_ *gorm.DB) error {
-	source := "ctx._source.comments += 1"
-	script := types.Script{Source: &source, Lang: &scriptlanguage.Painless}
-    _, err := global.ESClient.Update(elasticsearch.ArticleIndex(), c.ArticleID).Script(&script).Do(context.TODO())
-	return err
+// AfterCreate 钩子，创建后调用，审核通过的评论在同一事务中记录文章评论数的更新
+func (c *Comment) AfterCreate(tx *gorm.DB) error {
+	if c.Status != appTypes.CommentApproved {
+		return nil
+	}
+	return tx.Session(&gorm.Session{NewDB: true}).Create(NewCommentCountOutbox(c.ArticleID, 1)).Error
 }
 
-// AfterDelete 钩子，删除后调用
-func (c *Comment) BeforeDelete(_ *gorm.DB) error {
-	var articleID string
-	if err := global.DB.Model(&c).Pluck("article_id", &articleID).Error; err != nil {
-		return err
+// BeforeDelete 钩子，删除前调用，删除审核通过的评论时在同一事务中记录文章评论数的更新，占位评论已经不再计数
+func (c *Comment) BeforeDelete(tx *gorm.DB) error {
+	db := tx.Session(&gorm.Session{NewDB: true})
+	comment := *c
+	if comment.ArticleID == "" || comment.Status == "" {
+		if err := db.Select("article_id", "status", "deleted").Take(&comment, c.ID).Error; err != nil {
+			return err
+		}
 	}
-	source := "ctx._source.comments -= 1"
-	script := types.Script{Source: &source, Lang: &scriptlanguage.Painless}
-	_, err := global.ESClient.Update(elasticsearch.ArticleIndex(), articleID).Script(&script).Do(context.TODO())
-	return err
-}
\ No newline at end of file
+	if comment.ArticleID == "" || comment.Status != appTypes.CommentApproved || comment.Deleted {
+		return nil
+	}
+	return db.Create(NewCommentCountOutbox(comment.ArticleID, -1)).Error
+}
+
+// NewCommentCountOutbox 创建更新文章评论数的发件箱事件
+func NewCommentCountOutbox(articleID string, delta int) *EsOutbox {
+	script := "ctx._source.comments += 1"
+	if delta < 0 {
+		script = "ctx._source.comments -= 1"
+	}
+	return NewEsOutbox(appTypes.OutboxScript, elasticsearch.ArticleIndex(), articleID, script)
+}
@@ -0,0 +1,11 @@
+package database
+
+import "time"
+
+// CommentEdit 评论编辑历史表，每次编辑前保存一份原内容
+type CommentEdit struct {
+	ID        uint      `json:"id" gorm:"primarykey"`     // 主键 ID
+	CreatedAt time.Time `json:"created_at"`               // 编辑时间
+	CommentID uint      `json:"comment_id" gorm:"index"`  // 评论 ID
+	Content   string    `json:"content" gorm:"type:text"` // 编辑前的内容
+}
@@ -0,0 +1,15 @@
+package database
+
+import (
+	"server/model/appTypes"
+	"time"
+)
+
+// CommentReaction 评论回应表，每个用户对每条评论只能有一个回应
+type CommentReaction struct {
+	ID        uint                     `json:"id" gorm:"primarykey"`                           // 主键 ID
+	CreatedAt time.Time                `json:"created_at"`                                     // 创建时间
+	CommentID uint                     `json:"comment_id" gorm:"uniqueIndex:idx_comment_user"` // 评论 ID
+	UserID    uint                     `json:"user_id" gorm:"uniqueIndex:idx_comment_user"`    // 用户 ID
+	Reaction  appTypes.CommentReaction `json:"reaction" gorm:"size:16"`                        // 回应类型
+}
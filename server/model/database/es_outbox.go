@@ -0,0 +1,32 @@
+package database
+
+import (
+	"server/global"
+	"server/model/appTypes"
+	"time"
+)
+
+// EsOutbox ES 操作发件箱表，与 MySQL 数据在同一事务中写入，由后台任务投递到 ES
+type EsOutbox struct {
+	global.MODEL
+	Operation   appTypes.OutboxOperation `json:"operation" gorm:"size:16"`     // 操作类型
+	Index       string                   `json:"index" gorm:"size:64"`         // ES 索引
+	DocID       string                   `json:"doc_id" gorm:"size:64;index"`  // 文档 ID
+	Payload     string                   `json:"payload" gorm:"type:longtext"` // 文档 JSON 或脚本内容
+	Status      appTypes.OutboxStatus    `json:"status" gorm:"size:16;index"`  // 状态
+	Attempts    int                      `json:"attempts"`                     // 已重试次数
+	NextRetryAt time.Time                `json:"next_retry_at"`                // 下次投递时间
+	LastError   string                   `json:"last_error" gorm:"type:text"`  // 最近一次失败的原因
+}
+
+// NewEsOutbox 创建一个待投递的 ES 操作
+func NewEsOutbox(operation appTypes.OutboxOperation, index, docID, payload string) *EsOutbox {
+	return &EsOutbox{
+		Operation:   operation,
+		Index:       index,
+		DocID:       docID,
+		Payload:     payload,
+		Status:      appTypes.OutboxPending,
+		NextRetryAt: time.Now(),
+	}
+}
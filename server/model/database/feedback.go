@@ -12,4 +12,5 @@ type Feedback struct {
 	User     User      `json:"-" gorm:"foreignKey:UserUUID;references:UUID"` // 关联的用户
 	Content  string    `json:"content"`                                      // 内容
 	Reply    string    `json:"reply"`                                        // 回复
+	Pending  bool      `json:"pending" gorm:"default:false"`                 // 是否等待审核，审核通过前不公开展示
 }
\ No newline at end of file
@@ -0,0 +1,19 @@
+package database
+
+import (
+	"github.com/gofrs/uuid"
+	"server/global"
+	"server/model/appTypes"
+	"server/model/other"
+)
+
+// FilterLog 内容过滤记录表，记录每次命中过滤规则的内容和处理结果
+type FilterLog struct {
+	global.MODEL
+	Scene    string                `json:"scene" gorm:"size:16;index"`            // 场景，comment 或 feedback
+	UserUUID uuid.UUID             `json:"user_uuid" gorm:"type:char(36)"`        // 用户 uuid
+	Content  string                `json:"content" gorm:"type:text"`              // 原始内容
+	Result   string                `json:"result" gorm:"type:text"`               // 处理后的内容
+	Action   appTypes.FilterAction `json:"action" gorm:"size:16;index"`           // 最终的处理方式
+	Hits     []other.FilterHit     `json:"hits" gorm:"type:text;serializer:json"` // 命中的规则
+}
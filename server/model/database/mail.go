@@ -0,0 +1,28 @@
+package database
+
+import (
+	"server/global"
+	"server/model/appTypes"
+	"time"
+)
+
+// MailTemplate 邮件模板表，没有记录时使用内置的默认模板
+type MailTemplate struct {
+	Name      string    `json:"name" gorm:"size:64;primaryKey"` // 模板名称
+	Subject   string    `json:"subject" gorm:"size:255"`        // 邮件主题模板
+	HTML      string    `json:"html" gorm:"type:text"`          // HTML 正文模板
+	Text      string    `json:"text" gorm:"type:text"`          // 纯文本正文模板
+	UpdatedAt time.Time `json:"updated_at"`                     // 更新时间
+}
+
+// MailLog 邮件投递记录表，每次发送尝试记录一条
+type MailLog struct {
+	global.MODEL
+	MessageID string              `json:"message_id" gorm:"size:36;index"` // 邮件消息 ID，同一封邮件的多次尝试相同
+	Recipient string              `json:"recipient" gorm:"size:255;index"` // 收件人
+	Template  string              `json:"template" gorm:"size:64;index"`   // 模板名称
+	Subject   string              `json:"subject" gorm:"size:255"`         // 邮件主题
+	Status    appTypes.MailStatus `json:"status" gorm:"size:16;index"`     // 投递状态
+	Attempt   int                 `json:"attempt"`                         // 第几次尝试
+	Error     string              `json:"error" gorm:"type:text"`          // 失败原因
+}
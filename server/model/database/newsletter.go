@@ -0,0 +1,25 @@
+package database
+
+import (
+	"server/global"
+	"server/model/other"
+)
+
+// Subscriber 邮件订阅者表，只保存已确认订阅的邮箱
+type Subscriber struct {
+	global.MODEL
+	Email      string   `json:"email" gorm:"size:255;uniqueIndex"`           // 邮箱
+	Categories []string `json:"categories" gorm:"type:text;serializer:json"` // 订阅的类别，与标签都为空时订阅全部文章
+	Tags       []string `json:"tags" gorm:"type:text;serializer:json"`       // 订阅的标签
+}
+
+// NewsletterIssue 订阅邮件发送任务表，按订阅者 ID 分批发送
+type NewsletterIssue struct {
+	global.MODEL
+	Template  string                    `json:"template" gorm:"size:64"`                         // 邮件模板
+	ArticleID *string                   `json:"article_id" gorm:"size:64;uniqueIndex"`           // 新文章通知对应的文章，每篇文章只通知一次，每周摘要为空
+	Articles  []other.NewsletterArticle `json:"articles" gorm:"type:mediumtext;serializer:json"` // 邮件中的文章
+	Cursor    uint                      `json:"cursor"`                                          // 已处理到的订阅者 ID
+	Sent      int                       `json:"sent"`                                            // 已发送的邮件数
+	Done      bool                      `json:"done" gorm:"index"`                               // 是否已发送完毕
+}
@@ -0,0 +1,29 @@
+package database
+
+import (
+	"github.com/gofrs/uuid"
+	"server/global"
+	"server/model/appTypes"
+)
+
+// Notification 通知表
+type Notification struct {
+	global.MODEL
+	UserUUID   uuid.UUID                 `json:"-" gorm:"type:char(36);index"`                      // 接收者 uuid
+	ActorUUID  uuid.UUID                 `json:"actor_uuid" gorm:"type:char(36)"`                   // 触发者 uuid
+	Actor      User                      `json:"actor" gorm:"foreignKey:ActorUUID;references:UUID"` // 触发者
+	Type       appTypes.NotificationType `json:"type" gorm:"size:32"`                               // 通知类型
+	ArticleID  string                    `json:"article_id" gorm:"size:64"`                         // 相关文章 ID
+	CommentID  *uint                     `json:"comment_id"`                                        // 相关评论 ID
+	FeedbackID *uint                     `json:"feedback_id"`                                       // 相关反馈 ID
+	Content    string                    `json:"content"`                                           // 内容摘要
+	Read       bool                      `json:"read" gorm:"column:is_read;default:false;index"`    // 是否已读
+}
+
+// NotificationPreference 通知偏好表，没有记录时使用默认偏好：站内通知开启，邮件通知关闭
+type NotificationPreference struct {
+	UserUUID uuid.UUID                 `json:"-" gorm:"type:char(36);primaryKey"` // 用户 uuid
+	Type     appTypes.NotificationType `json:"type" gorm:"size:32;primaryKey"`    // 通知类型
+	InApp    bool                      `json:"in_app"`                            // 是否接收站内通知
+	Email    bool                      `json:"email"`                             // 是否接收邮件通知
+}
@@ -0,0 +1,13 @@
+package database
+
+import (
+	"server/global"
+	"server/model/appTypes"
+)
+
+// SensitiveWord 敏感词表
+type SensitiveWord struct {
+	global.MODEL
+	Word   string                `json:"word" gorm:"size:64;unique"` // 敏感词
+	Action appTypes.FilterAction `json:"action" gorm:"size:16"`      // 命中后的处理方式
+}
@@ -0,0 +1,12 @@
+package database
+
+import "server/model/appTypes"
+
+// TrafficDaily 站点每日流量统计表，每个维度的每个取值一条记录
+type TrafficDaily struct {
+	Date      string                    `json:"date" gorm:"type:date;primaryKey"`    // 日期，格式为 2006-01-02
+	Dimension appTypes.TrafficDimension `json:"dimension" gorm:"size:16;primaryKey"` // 统计维度
+	Value     string                    `json:"value" gorm:"size:255;primaryKey"`    // 维度取值
+	Hits      int                       `json:"hits"`                                // 访问次数
+	Duration  int64                     `json:"duration"`                            // 响应时间总和，单位为毫秒
+}
@@ -2,6 +2,9 @@ package elasticsearch
 
 import (
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"server/model/appTypes"
+	"server/model/other"
+	"strconv"
 )
 
 // Article 文章表
@@ -11,22 +14,50 @@ type Article struct {
 
 	Cover    string   `json:"cover"`    // 文章封面
 	Title    string   `json:"title"`    // 文章标题
+	Slug     string   `json:"slug"`     // 文章别名，用于生成可读的永久链接
 	Keyword  string   `json:"keyword"`  // 文章标题-关键字
 	Category string   `json:"category"` // 文章类别
 	Tags     []string `json:"tags"`     // 文章标签
 	Abstract string   `json:"abstract"` // 文章简介
 	Content  string   `json:"content"`  // 文章内容
 
+	ContentHTML string          `json:"content_html"` // 渲染后的文章内容
+	TOC         []other.TOCItem `json:"toc"`          // 文章目录
+	WordCount   int             `json:"word_count"`   // 字数
+	ReadingTime int             `json:"reading_time"` // 预计阅读时间，单位为分钟
+
 	Views    int `json:"views"`    // 浏览量
 	Comments int `json:"comments"` // 评论量
 	Likes    int `json:"likes"`    // 收藏量
+
+	Status    appTypes.ArticleStatus `json:"status"`               // 文章状态
+	PublishAt string                 `json:"publish_at,omitempty"` // 发布时间，定时发布的文章到达该时间后自动发布
 }
 
-// ArticleIndex 文章 ES 索引
+// IsPublished 判断文章是否已发布，没有状态字段的旧文章视为已发布
+func (a Article) IsPublished() bool {
+	return a.Status == "" || a.Status == appTypes.Published
+}
+
+// ArticleIndex 文章 ES 索引，实际为指向当前版本索引的别名
 func ArticleIndex() string {
 	return "article_index"
 }
 
+// ArticleIndexVersion 文章 ES 索引的指定版本，例如 article_index_v2
+func ArticleIndexVersion(version int) string {
+	return ArticleIndex() + "_v" + strconv.Itoa(version)
+}
+
+// ArticleTitleProperty 文章标题映射，title.suggest 子字段用于搜索自动补全
+func ArticleTitleProperty() types.TextProperty {
+	p := articleTextProperty("title")
+	p.Fields = map[string]types.Property{
+		"suggest": types.SearchAsYouTypeProperty{},
+	}
+	return p
+}
+
 // ArticleMapping 文章 Mapping 映射
 func ArticleMapping() *types.TypeMapping {
 	return &types.TypeMapping{
@@ -34,15 +65,25 @@ func ArticleMapping() *types.TypeMapping {
 			"created_at": types.DateProperty{NullValue: nil, Format: func(s string) *string { return &s }("yyyy-MM-dd HH:mm:ss")},
 			"updated_at": types.DateProperty{NullValue: nil, Format: func(s string) *string { return &s }("yyyy-MM-dd HH:mm:ss")},
 			"cover":      types.TextProperty{},
-			"title":      types.TextProperty{},
+			"title":      ArticleTitleProperty(),
+			"slug":       types.KeywordProperty{},
 			"keyword":    types.KeywordProperty{},
 			"category":   types.KeywordProperty{},
 			"tags":       []types.KeywordProperty{},
-			"abstract":   types.TextProperty{},
-			"content":    types.TextProperty{},
-			"views":      types.IntegerNumberProperty{},
-			"comments":   types.IntegerNumberProperty{},
-			"likes":      types.IntegerNumberProperty{},
+			"abstract":   articleTextProperty("abstract"),
+			"content":    articleTextProperty("content"),
+			// 渲染结果只用于展示，不参与检索
+			"content_html": types.TextProperty{Index: func(b bool) *bool { return &b }(false)},
+			"toc":          types.ObjectProperty{Enabled: func(b bool) *bool { return &b }(false)},
+			"word_count":   types.IntegerNumberProperty{},
+			"reading_time": types.IntegerNumberProperty{},
+			"views":        types.IntegerNumberProperty{},
+			"views_batch":  types.KeywordProperty{Index: func(b bool) *bool { return &b }(false)}, // 最近一次累加浏览量的批次，用于避免重试时重复累加
+			"comments":     types.IntegerNumberProperty{},
+			"likes":        types.IntegerNumberProperty{},
+			"outbox_seq":   types.LongNumberProperty{Index: func(b bool) *bool { return &b }(false)}, // 最近一次执行的发件箱脚本事件 ID，用于避免重试时重复执行
+			"status":       types.KeywordProperty{},
+			"publish_at":   types.DateProperty{NullValue: nil, Format: func(s string) *string { return &s }("yyyy-MM-dd HH:mm:ss")},
 		},
 	}
 }
\ No newline at end of file
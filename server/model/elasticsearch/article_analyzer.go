@@ -0,0 +1,49 @@
+package elasticsearch
+
+import (
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"server/config"
+	"server/global"
+)
+
+// FallbackAnalyzer 分词插件未安装时使用的内置分词器，按二元组切分中日韩文字
+const FallbackAnalyzer = "cjk"
+
+// ArticleTextFields 使用可配置分词器的文本字段
+var ArticleTextFields = []string{"title", "abstract", "content"}
+
+// unavailableAnalyzers 当前 ES 中不可用的分词器，启动时检测
+var unavailableAnalyzers = map[string]bool{}
+
+// SetAnalyzerUnavailable 标记分词器不可用，之后生成的映射会使用 FallbackAnalyzer 代替
+func SetAnalyzerUnavailable(name string) {
+	unavailableAnalyzers[name] = true
+}
+
+// ArticleAnalyzer 获取文本字段实际使用的分词器
+func ArticleAnalyzer(field string) config.ESAnalyzer {
+	a := global.Config.ES.Analyzers[field]
+	if a.Search == "" {
+		a.Search = a.Index
+	}
+	if unavailableAnalyzers[a.Index] {
+		a.Index = FallbackAnalyzer
+	}
+	if unavailableAnalyzers[a.Search] {
+		a.Search = FallbackAnalyzer
+	}
+	return a
+}
+
+// articleTextProperty 按配置的分词器生成文本字段映射
+func articleTextProperty(field string) types.TextProperty {
+	var p types.TextProperty
+	a := ArticleAnalyzer(field)
+	if a.Index != "" {
+		p.Analyzer = &a.Index
+	}
+	if a.Search != "" && a.Search != a.Index {
+		p.SearchAnalyzer = &a.Search
+	}
+	return p
+}
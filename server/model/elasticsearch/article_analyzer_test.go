@@ -0,0 +1,140 @@
+package elasticsearch
+
+import (
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"server/config"
+	"server/global"
+	"testing"
+)
+
+func TestArticleAnalyzer(t *testing.T) {
+	tests := []struct {
+		name        string
+		analyzers   map[string]config.ESAnalyzer
+		unavailable []string
+		field       string
+		want        config.ESAnalyzer
+	}{
+		{
+			name:  "not configured",
+			field: "title",
+			want:  config.ESAnalyzer{},
+		},
+		{
+			name:      "ik with separate search analyzer",
+			analyzers: map[string]config.ESAnalyzer{"title": {Index: "ik_max_word", Search: "ik_smart"}},
+			field:     "title",
+			want:      config.ESAnalyzer{Index: "ik_max_word", Search: "ik_smart"},
+		},
+		{
+			name:      "search analyzer defaults to index analyzer",
+			analyzers: map[string]config.ESAnalyzer{"content": {Index: "ik_max_word"}},
+			field:     "content",
+			want:      config.ESAnalyzer{Index: "ik_max_word", Search: "ik_max_word"},
+		},
+		{
+			name:        "ik plugin missing falls back to cjk",
+			analyzers:   map[string]config.ESAnalyzer{"title": {Index: "ik_max_word", Search: "ik_smart"}},
+			unavailable: []string{"ik_max_word", "ik_smart"},
+			field:       "title",
+			want:        config.ESAnalyzer{Index: FallbackAnalyzer, Search: FallbackAnalyzer},
+		},
+		{
+			name:        "only search analyzer missing",
+			analyzers:   map[string]config.ESAnalyzer{"abstract": {Index: "ik_max_word", Search: "ik_smart"}},
+			unavailable: []string{"ik_smart"},
+			field:       "abstract",
+			want:        config.ESAnalyzer{Index: "ik_max_word", Search: FallbackAnalyzer},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setAnalyzers(t, tt.analyzers, tt.unavailable...)
+			if got := ArticleAnalyzer(tt.field); got != tt.want {
+				t.Errorf("ArticleAnalyzer(%q) = %+v, want %+v", tt.field, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArticleMappingAnalyzers(t *testing.T) {
+	tests := []struct {
+		name        string
+		analyzers   map[string]config.ESAnalyzer
+		unavailable []string
+		// 字段名到期望的 analyzer 和 search_analyzer，空字符串表示不设置
+		want map[string][2]string
+	}{
+		{
+			name: "default analyzer",
+			want: map[string][2]string{"title": {"", ""}, "abstract": {"", ""}, "content": {"", ""}},
+		},
+		{
+			name: "ik",
+			analyzers: map[string]config.ESAnalyzer{
+				"title":    {Index: "ik_max_word", Search: "ik_smart"},
+				"abstract": {Index: "ik_max_word", Search: "ik_smart"},
+				"content":  {Index: "ik_max_word"},
+			},
+			want: map[string][2]string{
+				"title":    {"ik_max_word", "ik_smart"},
+				"abstract": {"ik_max_word", "ik_smart"},
+				"content":  {"ik_max_word", ""},
+			},
+		},
+		{
+			name: "cjk bigram fallback",
+			analyzers: map[string]config.ESAnalyzer{
+				"title":    {Index: "ik_max_word", Search: "ik_smart"},
+				"abstract": {Index: "ik_max_word", Search: "ik_smart"},
+				"content":  {Index: "ik_max_word", Search: "ik_smart"},
+			},
+			unavailable: []string{"ik_max_word", "ik_smart"},
+			want: map[string][2]string{
+				"title":    {FallbackAnalyzer, ""},
+				"abstract": {FallbackAnalyzer, ""},
+				"content":  {FallbackAnalyzer, ""},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setAnalyzers(t, tt.analyzers, tt.unavailable...)
+			mapping := ArticleMapping()
+			for field, want := range tt.want {
+				p, ok := mapping.Properties[field].(types.TextProperty)
+				if !ok {
+					t.Fatalf("%s is %T, want types.TextProperty", field, mapping.Properties[field])
+				}
+				if got := [2]string{deref(p.Analyzer), deref(p.SearchAnalyzer)}; got != want {
+					t.Errorf("%s analyzers = %q, want %q", field, got, want)
+				}
+			}
+			if _, ok := mapping.Properties["title"].(types.TextProperty).Fields["suggest"]; !ok {
+				t.Error("title.suggest sub-field is missing")
+			}
+		})
+	}
+}
+
+// setAnalyzers 设置测试使用的分词器配置和不可用的分词器，测试结束后恢复
+func setAnalyzers(t *testing.T, analyzers map[string]config.ESAnalyzer, unavailable ...string) {
+	oldConfig, oldUnavailable := global.Config, unavailableAnalyzers
+	t.Cleanup(func() {
+		global.Config, unavailableAnalyzers = oldConfig, oldUnavailable
+	})
+	global.Config = &config.Config{ES: config.ES{Analyzers: analyzers}}
+	unavailableAnalyzers = map[string]bool{}
+	for _, name := range unavailable {
+		SetAnalyzerUnavailable(name)
+	}
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
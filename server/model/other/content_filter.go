@@ -0,0 +1,10 @@
+package other
+
+import "server/model/appTypes"
+
+// FilterHit 内容过滤规则的命中结果
+type FilterHit struct {
+	Rule   string                `json:"rule"`   // 规则名称
+	Action appTypes.FilterAction `json:"action"` // 处理方式
+	Reason string                `json:"reason"` // 命中原因
+}
@@ -0,0 +1,9 @@
+package other
+
+// DiffLine 行级差异中的一行
+type DiffLine struct {
+	Type    string `json:"type"`     // 差异类型：equal 未变化，insert 新增，delete 删除
+	Content string `json:"content"`  // 行内容
+	OldLine int    `json:"old_line"` // 在旧文本中的行号，新增行为 0
+	NewLine int    `json:"new_line"` // 在新文本中的行号，删除行为 0
+}
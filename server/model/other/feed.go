@@ -0,0 +1,104 @@
+package other
+
+import "encoding/xml"
+
+// RSS RSS 2.0 订阅源
+type RSS struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel RSSChannel `xml:"channel"`
+}
+
+// RSSChannel RSS 频道信息
+type RSSChannel struct {
+	Title          string    `xml:"title"`                    // 频道标题
+	Link           string    `xml:"link"`                     // 网站链接
+	Description    string    `xml:"description"`              // 频道描述
+	Language       string    `xml:"language,omitempty"`       // 语言
+	ManagingEditor string    `xml:"managingEditor,omitempty"` // 作者邮箱及昵称
+	LastBuildDate  string    `xml:"lastBuildDate,omitempty"`  // 最后更新时间
+	AtomLink       AtomLink  `xml:"atom:link"`                // 订阅源自身链接
+	Items          []RSSItem `xml:"item"`                     // 文章列表
+}
+
+// RSSItem RSS 文章条目
+type RSSItem struct {
+	Title       string   `xml:"title"`              // 文章标题
+	Link        string   `xml:"link"`               // 文章链接
+	GUID        string   `xml:"guid"`               // 唯一标识
+	Description string   `xml:"description"`        // 文章摘要
+	Category    []string `xml:"category,omitempty"` // 类别及标签
+	PubDate     string   `xml:"pubDate"`            // 发布时间
+}
+
+// AtomFeed Atom 订阅源
+type AtomFeed struct {
+	XMLName  xml.Name    `xml:"feed"`
+	Xmlns    string      `xml:"xmlns,attr"`
+	ID       string      `xml:"id"`                 // 唯一标识
+	Title    string      `xml:"title"`              // 订阅源标题
+	Subtitle string      `xml:"subtitle,omitempty"` // 订阅源描述
+	Updated  string      `xml:"updated"`            // 最后更新时间
+	Links    []AtomLink  `xml:"link"`               // 网站链接及订阅源自身链接
+	Author   AtomAuthor  `xml:"author"`             // 作者
+	Entries  []AtomEntry `xml:"entry"`              // 文章列表
+}
+
+// AtomLink Atom 链接
+type AtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+// AtomAuthor Atom 作者
+type AtomAuthor struct {
+	Name  string `xml:"name"`
+	Email string `xml:"email,omitempty"`
+}
+
+// AtomCategory Atom 类别
+type AtomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// AtomEntry Atom 文章条目
+type AtomEntry struct {
+	ID         string         `xml:"id"`                 // 唯一标识
+	Title      string         `xml:"title"`              // 文章标题
+	Link       AtomLink       `xml:"link"`               // 文章链接
+	Published  string         `xml:"published"`          // 发布时间
+	Updated    string         `xml:"updated"`            // 更新时间
+	Summary    string         `xml:"summary"`            // 文章摘要
+	Categories []AtomCategory `xml:"category,omitempty"` // 类别及标签
+}
+
+// JSONFeed JSON Feed 1.1 订阅源
+type JSONFeed struct {
+	Version     string           `json:"version"`
+	Title       string           `json:"title"`                 // 订阅源标题
+	HomePageURL string           `json:"home_page_url"`         // 网站链接
+	FeedURL     string           `json:"feed_url"`              // 订阅源自身链接
+	Description string           `json:"description,omitempty"` // 订阅源描述
+	Authors     []JSONFeedAuthor `json:"authors,omitempty"`     // 作者
+	Items       []JSONFeedItem   `json:"items"`                 // 文章列表
+}
+
+// JSONFeedAuthor JSON Feed 作者
+type JSONFeedAuthor struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+// JSONFeedItem JSON Feed 文章条目
+type JSONFeedItem struct {
+	ID            string   `json:"id"`              // 唯一标识
+	URL           string   `json:"url"`             // 文章链接
+	Title         string   `json:"title"`           // 文章标题
+	ContentText   string   `json:"content_text"`    // 文章摘要
+	Image         string   `json:"image,omitempty"` // 文章封面
+	DatePublished string   `json:"date_published"`  // 发布时间
+	DateModified  string   `json:"date_modified"`   // 更新时间
+	Tags          []string `json:"tags,omitempty"`  // 类别及标签
+}
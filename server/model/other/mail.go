@@ -0,0 +1,11 @@
+package other
+
+// MailMessage 邮件队列中的消息
+type MailMessage struct {
+	ID       string         `json:"id"`       // 消息 ID
+	To       string         `json:"to"`       // 收件人
+	Template string         `json:"template"` // 模板名称
+	Data     map[string]any `json:"data"`     // 模板变量
+	Attempts int            `json:"attempts"` // 已尝试次数
+	Error    string         `json:"error"`    // 最近一次失败原因
+}
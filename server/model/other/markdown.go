@@ -0,0 +1,17 @@
+package other
+
+// TOCItem 文章目录中的一个标题
+type TOCItem struct {
+	ID       string    `json:"id"`                 // 标题锚点
+	Text     string    `json:"text"`               // 标题文本
+	Level    int       `json:"level"`              // 标题级别，1-6
+	Children []TOCItem `json:"children,omitempty"` // 子标题
+}
+
+// Markdown Markdown 渲染结果
+type Markdown struct {
+	HTML        string    // 经过清洗的 HTML
+	TOC         []TOCItem // 标题目录
+	WordCount   int       // 字数，中日韩文字按字计算，其余按单词计算
+	ReadingTime int       // 预计阅读时间，单位为分钟
+}
@@ -0,0 +1,11 @@
+package other
+
+// NewsletterArticle 订阅邮件中的文章摘要
+type NewsletterArticle struct {
+	ID       string   `json:"id"`       // 文章 ID
+	Title    string   `json:"title"`    // 标题
+	Abstract string   `json:"abstract"` // 简介
+	Category string   `json:"category"` // 类别
+	Tags     []string `json:"tags"`     // 标签
+	URL      string   `json:"url"`      // 文章链接
+}
@@ -0,0 +1,9 @@
+package other
+
+// CounterDrift 计数器的偏差
+type CounterDrift struct {
+	Kind     string `json:"kind"`     // 计数器类型：likes、comments、category、tag
+	Key      string `json:"key"`      // 文章 ID、类别或标签
+	Recorded int    `json:"recorded"` // 当前记录的值
+	Actual   int    `json:"actual"`   // 重新计算得到的实际值
+}
@@ -0,0 +1,30 @@
+package other
+
+import "encoding/xml"
+
+// SitemapURLSet 站点地图
+type SitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	URLs    []SitemapURL `xml:"url"`
+}
+
+// SitemapURL 站点地图中的一个页面
+type SitemapURL struct {
+	Loc        string `xml:"loc"`                  // 页面地址
+	LastMod    string `xml:"lastmod,omitempty"`    // 最后修改时间
+	ChangeFreq string `xml:"changefreq,omitempty"` // 更新频率
+}
+
+// SitemapIndex 站点地图索引，页面数量超过单个站点地图的上限时使用
+type SitemapIndex struct {
+	XMLName  xml.Name       `xml:"sitemapindex"`
+	Xmlns    string         `xml:"xmlns,attr"`
+	Sitemaps []SitemapEntry `xml:"sitemap"`
+}
+
+// SitemapEntry 站点地图索引中的一个站点地图
+type SitemapEntry struct {
+	Loc     string `xml:"loc"`               // 站点地图地址
+	LastMod string `xml:"lastmod,omitempty"` // 最后修改时间
+}
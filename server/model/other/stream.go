@@ -0,0 +1,12 @@
+package other
+
+import (
+	"encoding/json"
+	"server/model/appTypes"
+)
+
+// StreamMessage 通过 Redis 频道分发的推送消息
+type StreamMessage struct {
+	Event appTypes.StreamEvent `json:"event"`
+	Data  json.RawMessage      `json:"data"`
+}
@@ -0,0 +1,15 @@
+package other
+
+import "time"
+
+// TrafficRecord 一次请求的流量信息
+type TrafficRecord struct {
+	Path     string        // 访问的路由，例如 /api/article/:id
+	Referrer string        // 来源域名，直接访问为空
+	Country  string        // 访客所在国家，只区分国内和境外
+	Province string        // 访客所在省份，境外访客记为境外
+	Device   string        // 设备
+	OS       string        // 操作系统
+	Browser  string        // 浏览器
+	Duration time.Duration // 响应时间
+}
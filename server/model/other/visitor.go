@@ -0,0 +1,7 @@
+package other
+
+// Visitor 文章的访客
+type Visitor struct {
+	ID       string // 访客标识，已登录用户为 user:<ID>，游客为 guest:<指纹>
+	Referrer string // 来源域名，直接访问为空
+}
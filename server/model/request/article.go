@@ -1,5 +1,7 @@
 package request
 
+import "server/model/appTypes"
+
 type ArticleInfoByID struct {
 	ID string `json:"id" form:"id" uri:"id" binding:"required"`
 }
@@ -13,6 +15,14 @@ type ArticleSearch struct {
 	PageInfo
 }
 
+type ArticleSuggest struct {
+	Query string `json:"query" form:"query" binding:"required,max=50"`
+}
+
+type ArticleInfoBySlug struct {
+	Slug string `json:"slug" form:"slug" uri:"slug" binding:"required"`
+}
+
 type ArticleLike struct {
 	UserID    uint   `json:"-"`
 	ArticleID string `json:"article_id" form:"article_id" binding:"required"`
@@ -24,12 +34,17 @@ type ArticleLikesList struct {
 }
 
 type ArticleCreate struct {
+	UserID   uint     `json:"-"`
 	Cover    string   `json:"cover" binding:"required"`
 	Title    string   `json:"title" binding:"required"`
+	Slug     string   `json:"slug" binding:"max=80"` // 为空时根据标题自动生成
 	Category string   `json:"category" binding:"required"`
 	Tags     []string `json:"tags" binding:"required"`
 	Abstract string   `json:"abstract" binding:"required"`
 	Content  string   `json:"content" binding:"required"`
+
+	Status    appTypes.ArticleStatus `json:"status" binding:"omitempty,oneof=draft scheduled published archived"` // 为空时直接发布
+	PublishAt string                 `json:"publish_at"`                                                          // 定时发布时间，格式为 2006-01-02 15:04:05
 }
 
 type ArticleDelete struct {
@@ -37,18 +52,56 @@ type ArticleDelete struct {
 }
 
 type ArticleUpdate struct {
+	UserID   uint     `json:"-"`
 	ID       string   `json:"id" binding:"required"`
 	Cover    string   `json:"cover" binding:"required"`
 	Title    string   `json:"title" binding:"required"`
+	Slug     string   `json:"slug" binding:"max=80"` // 为空时保持原别名
 	Category string   `json:"category" binding:"required"`
 	Tags     []string `json:"tags" binding:"required"`
 	Abstract string   `json:"abstract" binding:"required"`
 	Content  string   `json:"content" binding:"required"`
+
+	Status    appTypes.ArticleStatus `json:"status" binding:"omitempty,oneof=draft scheduled published archived"` // 为空时保持原状态
+	PublishAt string                 `json:"publish_at"`                                                          // 定时发布时间，格式为 2006-01-02 15:04:05
 }
 
 type ArticleList struct {
-	Title    *string `json:"title" form:"title"`
-	Category *string `json:"category" form:"category"`
-	Abstract *string `json:"abstract" form:"abstract"`
+	Title    *string                 `json:"title" form:"title"`
+	Category *string                 `json:"category" form:"category"`
+	Abstract *string                 `json:"abstract" form:"abstract"`
+	Status   *appTypes.ArticleStatus `json:"status" form:"status"`
+	PageInfo
+}
+
+type ArticleRevisionList struct {
+	ArticleID string `json:"article_id" form:"article_id" binding:"required"`
 	PageInfo
-}
\ No newline at end of file
+}
+
+type ArticleRevisionDiff struct {
+	From uint `json:"from" form:"from" binding:"required"`
+	To   uint `json:"to" form:"to" binding:"required"`
+}
+
+type ArticleRevisionRestore struct {
+	UserID uint `json:"-"`
+	ID     uint `json:"id" binding:"required"`
+}
+
+// StatsRange 统计的日期范围，同时指定 start 和 end 时按日期范围统计，否则统计最近 date 天
+type StatsRange struct {
+	Date  int    `json:"date" form:"date" binding:"required_without=Start,omitempty,oneof=7 30 90 180 365"`
+	Start string `json:"start" form:"start" binding:"required_with=End,omitempty,datetime=2006-01-02"`
+	End   string `json:"end" form:"end" binding:"required_with=Start,omitempty,datetime=2006-01-02"`
+}
+
+type ArticleStats struct {
+	StatsRange
+}
+
+type ArticleStatsTop struct {
+	StatsRange
+	Metric string `json:"metric" form:"metric" binding:"required,oneof=views visitors likes comments"`
+	Size   int    `json:"size" form:"size"`
+}
@@ -1,7 +1,10 @@
 package request
 
+import "server/model/appTypes"
+
 type SendEmailVerificationCode struct {
-	Email     string `json:"email" binding:"required,email"`
-	Captcha   string `json:"captcha" binding:"required,len=6"`
-	CaptchaID string `json:"captcha_id" binding:"required"`
-}
\ No newline at end of file
+	Email     string                       `json:"email" binding:"required,email"`
+	Purpose   appTypes.VerificationPurpose `json:"purpose" binding:"required,oneof=register forgot_password change_email"`
+	Captcha   string                       `json:"captcha" binding:"required,len=6"`
+	CaptchaID string                       `json:"captcha_id" binding:"required"`
+}
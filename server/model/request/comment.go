@@ -1,16 +1,23 @@
 package request
 
-import "github.com/gofrs/uuid"
+import (
+	"github.com/gofrs/uuid"
+	"server/model/appTypes"
+)
 
 type CommentInfoByArticleID struct {
-	ArticleID string `json:"article_id" uri:"article_id" binding:"required"`
+	ArticleID string               `json:"article_id" uri:"article_id" binding:"required"`
+	Cursor    string               `json:"cursor" form:"cursor"`                                                // 上一页返回的游标
+	PageSize  int                  `json:"page_size" form:"page_size"`                                          // 每页一级评论的数量
+	Sort      appTypes.CommentSort `json:"sort" form:"sort" binding:"omitempty,oneof=oldest newest most_liked"` // 排序方式，默认最早发布在前
 }
 
 type CommentCreate struct {
-	UserUUID  uuid.UUID `json:"-"`
-	ArticleID string    `json:"article_id" binding:"required"`
-	PID       *uint     `json:"p_id"`
-	Content   string    `json:"content" binding:"required,max=320"`
+	UserUUID  uuid.UUID       `json:"-"`
+	RoleID    appTypes.RoleID `json:"-"`
+	ArticleID string          `json:"article_id" binding:"required"`
+	PID       *uint           `json:"p_id"`
+	Content   string          `json:"content" binding:"required,max=320"`
 }
 
 type CommentDelete struct {
@@ -18,8 +25,39 @@ type CommentDelete struct {
 }
 
 type CommentList struct {
-	ArticleID *string `json:"article_id" form:"article_id"`
-	UserUUID  *string `json:"user_uuid" form:"user_uuid"`
-	Content   *string `json:"content" form:"content"`
+	ArticleID *string                 `json:"article_id" form:"article_id"`
+	UserUUID  *string                 `json:"user_uuid" form:"user_uuid"`
+	Content   *string                 `json:"content" form:"content"`
+	Status    *appTypes.CommentStatus `json:"status" form:"status"`
 	PageInfo
-}
\ No newline at end of file
+}
+
+type CommentModerate struct {
+	IDs    []uint                 `json:"ids" binding:"required"`
+	Status appTypes.CommentStatus `json:"status" binding:"required,oneof=approved rejected spam"`
+}
+
+type CommentReact struct {
+	UserID    uint                     `json:"-"`
+	UserUUID  uuid.UUID                `json:"-"`
+	CommentID uint                     `json:"comment_id" binding:"required"`
+	Reaction  appTypes.CommentReaction `json:"reaction" binding:"required,oneof=like heart laugh hooray confused rocket"`
+}
+
+type CommentPin struct {
+	ID     uint  `json:"id" binding:"required"`
+	Pinned *bool `json:"pinned" binding:"required"`
+}
+
+type CommentUpdate struct {
+	UserUUID uuid.UUID       `json:"-"`
+	RoleID   appTypes.RoleID `json:"-"`
+	ID       uint            `json:"id" binding:"required"`
+	Content  string          `json:"content" binding:"required,max=320"`
+}
+
+type CommentHistory struct {
+	UserUUID uuid.UUID       `json:"-"`
+	RoleID   appTypes.RoleID `json:"-"`
+	ID       uint            `json:"id" form:"id" binding:"required"`
+}
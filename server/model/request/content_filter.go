@@ -0,0 +1,24 @@
+package request
+
+import "server/model/appTypes"
+
+type SensitiveWordList struct {
+	Word   *string                `json:"word" form:"word"`
+	Action *appTypes.FilterAction `json:"action" form:"action"`
+	PageInfo
+}
+
+type SensitiveWordCreate struct {
+	Words  []string              `json:"words" binding:"required,dive,required,max=64"`
+	Action appTypes.FilterAction `json:"action" binding:"required,oneof=mask moderate reject"`
+}
+
+type SensitiveWordDelete struct {
+	IDs []uint `json:"ids"`
+}
+
+type FilterLogList struct {
+	Scene  *string                `json:"scene" form:"scene"`
+	Action *appTypes.FilterAction `json:"action" form:"action"`
+	PageInfo
+}
@@ -0,0 +1,13 @@
+package request
+
+import "server/model/appTypes"
+
+type OutboxList struct {
+	Status *appTypes.OutboxStatus `json:"status" form:"status"`
+	DocID  *string                `json:"doc_id" form:"doc_id"`
+	PageInfo
+}
+
+type OutboxRetry struct {
+	IDs []uint `json:"ids" binding:"required"`
+}
@@ -0,0 +1,7 @@
+package request
+
+type Feed struct {
+	Format   string `json:"format" uri:"format" binding:"required,oneof=rss atom json"`
+	Category string `json:"category" uri:"category"`
+	Tag      string `json:"tag" uri:"tag"`
+}
@@ -11,7 +11,12 @@ type FeedbackDelete struct {
 	IDs []uint `json:"ids"`
 }
 
+type FeedbackApprove struct {
+	IDs []uint `json:"ids"`
+}
+
 type FeedbackReply struct {
-	ID    uint   `json:"id" binding:"required"`
-	Reply string `json:"reply" binding:"required"`
-}
\ No newline at end of file
+	UUID  uuid.UUID `json:"-"`
+	ID    uint      `json:"id" binding:"required"`
+	Reply string    `json:"reply" binding:"required"`
+}
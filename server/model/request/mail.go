@@ -0,0 +1,25 @@
+package request
+
+import "server/model/appTypes"
+
+type MailTemplateUpdate struct {
+	Name    string `json:"name" binding:"required"`
+	Subject string `json:"subject" binding:"required,max=255"`
+	HTML    string `json:"html" binding:"required"`
+	Text    string `json:"text"` // 为空时只发送 HTML 正文
+}
+
+type MailTemplateReset struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type MailLogList struct {
+	Recipient *string              `json:"recipient" form:"recipient"`
+	Template  *string              `json:"template" form:"template"`
+	Status    *appTypes.MailStatus `json:"status" form:"status"`
+	PageInfo
+}
+
+type MailDeadRetry struct {
+	IDs []string `json:"ids"` // 为空时重试全部死信
+}
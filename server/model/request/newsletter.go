@@ -0,0 +1,23 @@
+package request
+
+type NewsletterSubscribe struct {
+	Email      string   `json:"email" binding:"required,email"`
+	Categories []string `json:"categories"` // 订阅的类别，与标签都为空时订阅全部文章
+	Tags       []string `json:"tags"`
+	Captcha    string   `json:"captcha,omitempty" binding:"required,len=6"`
+	CaptchaID  string   `json:"captcha_id,omitempty" binding:"required"`
+}
+
+type NewsletterLink struct {
+	Email     string `json:"email" form:"email" binding:"required,email"`
+	Signature string `json:"sig" form:"sig" binding:"required"`
+}
+
+type SubscriberList struct {
+	Email *string `json:"email" form:"email"`
+	PageInfo
+}
+
+type SubscriberDelete struct {
+	IDs []uint `json:"ids"`
+}
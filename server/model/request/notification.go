@@ -0,0 +1,29 @@
+package request
+
+import (
+	"github.com/gofrs/uuid"
+	"server/model/appTypes"
+)
+
+type NotificationList struct {
+	UserUUID uuid.UUID `json:"-"`
+	Unread   bool      `json:"unread" form:"unread"` // 只获取未读通知
+	PageInfo
+}
+
+type NotificationRead struct {
+	UserUUID uuid.UUID `json:"-"`
+	IDs      []uint    `json:"ids"`
+	All      bool      `json:"all"` // 将所有通知标记为已读
+}
+
+type NotificationPreferenceUpdate struct {
+	UserUUID    uuid.UUID                       `json:"-"`
+	Preferences []NotificationPreferenceSetting `json:"preferences" binding:"required,dive"`
+}
+
+type NotificationPreferenceSetting struct {
+	Type  appTypes.NotificationType `json:"type" binding:"required,oneof=comment_reply mention feedback_reply comment_like"`
+	InApp bool                      `json:"in_app"`
+	Email bool                      `json:"email"`
+}
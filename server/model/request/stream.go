@@ -0,0 +1,5 @@
+package request
+
+type Stream struct {
+	ArticleID string `json:"article_id" form:"article_id"` // 正在浏览的文章，为空时不推送评论
+}
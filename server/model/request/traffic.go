@@ -0,0 +1,5 @@
+package request
+
+type TrafficStats struct {
+	Date int `json:"date" form:"date" binding:"required,oneof=1 7 30 90 180 365"`
+}
@@ -20,6 +20,13 @@ type ForgotPassword struct {
 	NewPassword      string `json:"new_password" binding:"required,min=8,max=16"`
 }
 
+type UserChangeEmail struct {
+	UserID           uint   `json:"-"`
+	Password         string `json:"password" binding:"required,min=8,max=16"`
+	Email            string `json:"email" binding:"required,email"`
+	VerificationCode string `json:"verification_code" binding:"required,len=6"`
+}
+
 type UserCard struct {
 	UUID string `json:"uuid" form:"uuid" binding:"required"`
 }
@@ -0,0 +1,59 @@
+package response
+
+import (
+	"server/model/database"
+	"server/model/elasticsearch"
+	"server/model/other"
+)
+
+type ArticleInfoBySlug struct {
+	ID         string                `json:"id"`         // 文章 ID
+	Slug       string                `json:"slug"`       // 文章当前的别名
+	Redirected bool                  `json:"redirected"` // 是否通过旧别名访问，为 true 时前端应跳转到当前别名
+	Article    elasticsearch.Article `json:"article"`
+}
+
+type ArticleRevisionDiff struct {
+	From     database.ArticleRevision `json:"from"`
+	To       database.ArticleRevision `json:"to"`
+	Title    []other.DiffLine         `json:"title"`
+	Abstract []other.DiffLine         `json:"abstract"`
+	Content  []other.DiffLine         `json:"content"`
+	Category []other.DiffLine         `json:"category"`
+	Cover    []other.DiffLine         `json:"cover"`
+	Tags     []other.DiffLine         `json:"tags"`
+}
+
+type ArticleRelated struct {
+	ID      string                `json:"_id"`
+	Article elasticsearch.Article `json:"_source"`
+}
+
+type ArticleSuggest struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Slug  string `json:"slug"`
+}
+
+type ArticleStats struct {
+	DateList    []string          `json:"date_list"`
+	ViewData    []int             `json:"view_data"`
+	VisitorData []int             `json:"visitor_data"`
+	LikeData    []int             `json:"like_data"`
+	CommentData []int             `json:"comment_data"`
+	Referrers   []ArticleReferrer `json:"referrers"`
+}
+
+type ArticleReferrer struct {
+	Referrer string `json:"referrer"`
+	Visits   int    `json:"visits"`
+}
+
+type ArticleStatsTop struct {
+	ArticleID string `json:"article_id"`
+	Title     string `json:"title"`
+	Views     int    `json:"views"`
+	Visitors  int    `json:"visitors"`
+	Likes     int    `json:"likes"`
+	Comments  int    `json:"comments"`
+}
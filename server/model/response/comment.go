@@ -0,0 +1,8 @@
+package response
+
+import "server/model/database"
+
+type CommentCursor struct {
+	List       []database.Comment `json:"list"`
+	NextCursor *string            `json:"next_cursor"` // 下一页的游标，没有下一页时为空
+}
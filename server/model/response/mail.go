@@ -0,0 +1,10 @@
+package response
+
+import "server/model/database"
+
+type MailTemplate struct {
+	database.MailTemplate
+	Description string   `json:"description"` // 模板用途
+	Variables   []string `json:"variables"`   // 可用的模板变量，网站信息统一通过 .Website 访问
+	Customized  bool     `json:"customized"`  // 是否已被管理员修改
+}
@@ -0,0 +1,19 @@
+package response
+
+type TrafficStats struct {
+	Hits        int           `json:"hits"`
+	AvgDuration int64         `json:"avg_duration"`
+	Pages       []TrafficItem `json:"pages"`
+	Referrers   []TrafficItem `json:"referrers"`
+	Countries   []TrafficItem `json:"countries"`
+	Provinces   []TrafficItem `json:"provinces"`
+	Devices     []TrafficItem `json:"devices"`
+	OS          []TrafficItem `json:"os"`
+	Browsers    []TrafficItem `json:"browsers"`
+}
+
+type TrafficItem struct {
+	Value       string `json:"value"`
+	Hits        int    `json:"hits"`
+	AvgDuration int64  `json:"avg_duration"`
+}
@@ -21,7 +21,10 @@ func (a *ArticleRouter) InitArticleRouter(Router *gin.RouterGroup, PublicRouter
 	}
 	{
 		articlePublicRouter.GET(":id", articleApi.ArticleInfoByID)
+		articlePublicRouter.GET(":id/related", articleApi.ArticleRelated)
+		articlePublicRouter.GET("slug/:slug", articleApi.ArticleInfoBySlug)
 		articlePublicRouter.GET("search", articleApi.ArticleSearch)
+		articlePublicRouter.GET("suggest", articleApi.ArticleSuggest)
 		articlePublicRouter.GET("category", articleApi.ArticleCategory)
 		articlePublicRouter.GET("tags", articleApi.ArticleTags)
 	}
@@ -30,5 +33,9 @@ func (a *ArticleRouter) InitArticleRouter(Router *gin.RouterGroup, PublicRouter
 		articleAdminRouter.DELETE("delete", articleApi.ArticleDelete)
 		articleAdminRouter.PUT("update", articleApi.ArticleUpdate)
 		articleAdminRouter.GET("list", articleApi.ArticleList)
+		articleAdminRouter.GET("revisionList", articleApi.ArticleRevisionList)
+		articleAdminRouter.GET("revisionDiff", articleApi.ArticleRevisionDiff)
+		articleAdminRouter.POST("revisionRestore", articleApi.ArticleRevisionRestore)
+		articleAdminRouter.GET(":id/stats", articleApi.ArticleStats)
 	}
 }
\ No newline at end of file
@@ -17,8 +17,11 @@ func (c *CommentRouter) InitCommentRouter(Router *gin.RouterGroup, PublicRouter
 	commentApi := api.ApiGroupApp.CommentApi
 	{
 		commentRouter.POST("create", commentApi.CommentCreate)
+		commentRouter.PUT("update", commentApi.CommentUpdate)
+		commentRouter.GET("history", commentApi.CommentHistory)
 		commentRouter.DELETE("delete", commentApi.CommentDelete)
 		commentRouter.GET("info", commentApi.CommentInfo)
+		commentRouter.POST("react", commentApi.CommentReact)
 	}
 	{
 		commentPublicRouter.GET(":article_id", commentApi.CommentInfoByArticleID)
@@ -26,5 +29,7 @@ func (c *CommentRouter) InitCommentRouter(Router *gin.RouterGroup, PublicRouter
 	}
 	{
 		commentAdminRouter.GET("list", commentApi.CommentList)
+		commentAdminRouter.PUT("moderate", commentApi.CommentModerate)
+		commentAdminRouter.PUT("pin", commentApi.CommentPin)
 	}
 }
\ No newline at end of file
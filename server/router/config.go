@@ -27,5 +27,11 @@ func (c *ConfigRouter) InitConfigRouter(Router *gin.RouterGroup) {
 		configRouter.PUT("jwt", configApi.UpdateJwt)
 		configRouter.GET("gaode", configApi.GetGaode)
 		configRouter.PUT("gaode", configApi.UpdateGaode)
+		configRouter.GET("comment", configApi.GetComment)
+		configRouter.PUT("comment", configApi.UpdateComment)
+		configRouter.GET("filter", configApi.GetFilter)
+		configRouter.PUT("filter", configApi.UpdateFilter)
+		configRouter.GET("newsletter", configApi.GetNewsletter)
+		configRouter.PUT("newsletter", configApi.UpdateNewsletter)
 	}
 }
\ No newline at end of file
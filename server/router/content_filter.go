@@ -0,0 +1,21 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"server/api"
+)
+
+type ContentFilterRouter struct {
+}
+
+func (f *ContentFilterRouter) InitContentFilterRouter(Router *gin.RouterGroup) {
+	filterRouter := Router.Group("filter")
+
+	contentFilterApi := api.ApiGroupApp.ContentFilterApi
+	{
+		filterRouter.GET("words", contentFilterApi.SensitiveWordList)
+		filterRouter.POST("words", contentFilterApi.SensitiveWordCreate)
+		filterRouter.DELETE("words", contentFilterApi.SensitiveWordDelete)
+		filterRouter.GET("logs", contentFilterApi.FilterLogList)
+	}
+}
@@ -11,6 +11,16 @@ type RouterGroup struct {
 	FeedbackRouter
 	WebsiteRouter
 	ConfigRouter
+	FeedRouter
+	SitemapRouter
+	OutboxRouter
+	StatsRouter
+	TrafficRouter
+	ContentFilterRouter
+	NotificationRouter
+	StreamRouter
+	MailRouter
+	NewsletterRouter
 }
 
 var RouterGroupApp = new(RouterGroup)
\ No newline at end of file
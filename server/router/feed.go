@@ -0,0 +1,20 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"server/api"
+)
+
+type FeedRouter struct {
+}
+
+func (f *FeedRouter) InitFeedRouter(PublicRouter *gin.RouterGroup) {
+	feedPublicRouter := PublicRouter.Group("feed")
+
+	feedApi := api.ApiGroupApp.FeedApi
+	{
+		feedPublicRouter.GET(":format", feedApi.Feed)
+		feedPublicRouter.GET(":format/category/:category", feedApi.Feed)
+		feedPublicRouter.GET(":format/tag/:tag", feedApi.Feed)
+	}
+}
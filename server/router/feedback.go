@@ -24,6 +24,7 @@ func (f *FeedbackRouter) InitFeedbackRouter(Router *gin.RouterGroup, PublicRoute
 	{
 		feedbackAdminRouter.DELETE("delete", feedbackApi.FeedbackDelete)
 		feedbackAdminRouter.PUT("reply", feedbackApi.FeedbackReply)
+		feedbackAdminRouter.PUT("approve", feedbackApi.FeedbackApprove)
 		feedbackAdminRouter.GET("list", feedbackApi.FeedbackList)
 	}
 }
\ No newline at end of file
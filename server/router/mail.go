@@ -0,0 +1,23 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"server/api"
+)
+
+type MailRouter struct {
+}
+
+func (m *MailRouter) InitMailRouter(Router *gin.RouterGroup) {
+	mailRouter := Router.Group("mail")
+
+	mailApi := api.ApiGroupApp.MailApi
+	{
+		mailRouter.GET("templates", mailApi.MailTemplateList)
+		mailRouter.PUT("template", mailApi.MailTemplateUpdate)
+		mailRouter.DELETE("template", mailApi.MailTemplateReset)
+		mailRouter.GET("logs", mailApi.MailLogList)
+		mailRouter.GET("dead", mailApi.MailDeadList)
+		mailRouter.POST("dead/retry", mailApi.MailDeadRetry)
+	}
+}
@@ -0,0 +1,27 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"server/api"
+)
+
+type NewsletterRouter struct {
+}
+
+func (n *NewsletterRouter) InitNewsletterRouter(Router *gin.RouterGroup, PublicRouter *gin.RouterGroup) {
+	newsletterRouter := Router.Group("newsletter")
+	newsletterPublicRouter := PublicRouter.Group("newsletter")
+
+	newsletterApi := api.ApiGroupApp.NewsletterApi
+	{
+		newsletterRouter.GET("subscribers", newsletterApi.SubscriberList)
+		newsletterRouter.DELETE("subscribers", newsletterApi.SubscriberDelete)
+	}
+	{
+		newsletterPublicRouter.POST("subscribe", newsletterApi.NewsletterSubscribe)
+		newsletterPublicRouter.GET("confirm", newsletterApi.NewsletterConfirmPage)
+		newsletterPublicRouter.POST("confirm", newsletterApi.NewsletterConfirm)
+		newsletterPublicRouter.GET("unsubscribe", newsletterApi.NewsletterUnsubscribePage)
+		newsletterPublicRouter.POST("unsubscribe", newsletterApi.NewsletterUnsubscribe)
+	}
+}
@@ -0,0 +1,22 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"server/api"
+)
+
+type NotificationRouter struct {
+}
+
+func (n *NotificationRouter) InitNotificationRouter(Router *gin.RouterGroup) {
+	notificationRouter := Router.Group("notification")
+
+	notificationApi := api.ApiGroupApp.NotificationApi
+	{
+		notificationRouter.GET("list", notificationApi.NotificationList)
+		notificationRouter.GET("unread", notificationApi.NotificationUnread)
+		notificationRouter.PUT("read", notificationApi.NotificationRead)
+		notificationRouter.GET("preferences", notificationApi.NotificationPreferences)
+		notificationRouter.PUT("preferences", notificationApi.NotificationPreferenceUpdate)
+	}
+}
@@ -0,0 +1,19 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"server/api"
+)
+
+type OutboxRouter struct {
+}
+
+func (o *OutboxRouter) InitOutboxRouter(Router *gin.RouterGroup) {
+	outboxRouter := Router.Group("outbox")
+
+	outboxApi := api.ApiGroupApp.OutboxApi
+	{
+		outboxRouter.GET("list", outboxApi.OutboxList)
+		outboxRouter.POST("retry", outboxApi.OutboxRetry)
+	}
+}
@@ -0,0 +1,19 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"server/api"
+)
+
+type SitemapRouter struct {
+}
+
+// InitSitemapRouter 站点地图和 robots.txt 需要挂载在网站根路径下，供搜索引擎抓取
+func (s *SitemapRouter) InitSitemapRouter(RootRouter *gin.RouterGroup) {
+	sitemapApi := api.ApiGroupApp.SitemapApi
+	{
+		RootRouter.GET("sitemap.xml", sitemapApi.Sitemap)
+		RootRouter.GET("sitemap/:name", sitemapApi.SitemapPart)
+		RootRouter.GET("robots.txt", sitemapApi.Robots)
+	}
+}
@@ -0,0 +1,18 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"server/api"
+)
+
+type StatsRouter struct {
+}
+
+func (s *StatsRouter) InitStatsRouter(Router *gin.RouterGroup) {
+	statsRouter := Router.Group("stats")
+
+	articleApi := api.ApiGroupApp.ArticleApi
+	{
+		statsRouter.GET("articles/top", articleApi.ArticleStatsTop)
+	}
+}
@@ -0,0 +1,18 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"server/api"
+)
+
+type StreamRouter struct {
+}
+
+func (s *StreamRouter) InitStreamRouter(Router *gin.RouterGroup) {
+	streamRouter := Router.Group("stream")
+
+	streamApi := api.ApiGroupApp.StreamApi
+	{
+		streamRouter.GET("events", streamApi.Stream)
+	}
+}
@@ -0,0 +1,18 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"server/api"
+)
+
+type TrafficRouter struct {
+}
+
+func (t *TrafficRouter) InitTrafficRouter(Router *gin.RouterGroup) {
+	trafficRouter := Router.Group("traffic")
+
+	trafficApi := api.ApiGroupApp.TrafficApi
+	{
+		trafficRouter.GET("stats", trafficApi.TrafficStats)
+	}
+}
@@ -37,6 +37,8 @@ func (u *UserRouter) InitUserRouter(Router *gin.RouterGroup, PublicRouter *gin.R
 		userRouter.GET("info", userApi.UserInfo)
 		// 处理用户修改信息请求
 		userRouter.PUT("changeInfo", userApi.UserChangeInfo)
+		// 处理用户修改邮箱请求
+		userRouter.PUT("changeEmail", userApi.UserChangeEmail)
 		// 处理获取用户天气信息请求
 		userRouter.GET("weather", userApi.UserWeather)
 		// 处理获取用户图表信息请求
@@ -5,8 +5,10 @@ import (
 	"errors"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
-	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/scriptlanguage"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/highlighterencoder"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/sortorder"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 	"server/global"
 	"server/model/appTypes"
@@ -22,17 +24,59 @@ import (
 type ArticleService struct {
 }
 
-func (articleService *ArticleService) ArticleInfoByID(id string) (elasticsearch.Article, error) {
+// ArticleInfoByID 获取文章内容并记录访客的浏览，访客标识为空时不计浏览量
+func (articleService *ArticleService) ArticleInfoByID(id string, visitor other.Visitor) (elasticsearch.Article, error) {
+	article, err := articleService.Get(id)
+	if err != nil {
+		return elasticsearch.Article{}, err
+	}
+	// 未发布的文章不对外展示
+	if !article.IsPublished() {
+		return elasticsearch.Article{}, errors.New("document not found")
+	}
+	// 历史文章没有渲染结果时临时渲染
+	if article.ContentHTML == "" && article.Content != "" {
+		md, err := utils.RenderMarkdown(article.Content)
+		if err != nil {
+			return elasticsearch.Article{}, err
+		}
+		article.ContentHTML, article.TOC, article.WordCount, article.ReadingTime = md.HTML, md.TOC, md.WordCount, md.ReadingTime
+	}
 	// 异步更新浏览量
-	go func() {
-		articleView := articleService.NewArticleView()
-		_ = articleView.Set(id)
-	}()
-	return articleService.Get(id)
+	if visitor.ID != "" {
+		go func() {
+			if err := articleService.RecordVisit(id, visitor); err != nil {
+				global.Log.Error("Failed to count article view:", zap.Error(err))
+			}
+		}()
+	}
+	return article, nil
 }
 
 // ArticleSearch 该函数用于根据传入的查询条件在 Elasticsearch 中搜索文章信息，并返回搜索结果、结果总数和可能出现的错误
 func (articleService *ArticleService) ArticleSearch(info request.ArticleSearch) (interface{}, int64, error) {
+	// 构建 Elasticsearch 查询选项
+	option := other.EsOption{
+		PageInfo:       info.PageInfo,                                                                                          // 分页信息
+		Index:          elasticsearch.ArticleIndex(),                                                                           // 要查询的 Elasticsearch 索引
+		Request:        articleSearchRequest(info),                                                                             // Elasticsearch 请求对象
+		SourceIncludes: []string{"created_at", "cover", "title", "abstract", "category", "tags", "views", "comments", "likes"}, // 要返回的字段
+	}
+	// 调用工具函数进行 Elasticsearch 分页查询，并返回结果
+	return utils.EsPagination(context.TODO(), option)
+}
+
+// articleTextMatch 匹配文章的文本字段，使用配置的搜索分词器处理中英文混合的查询，文本字段允许一定的拼写错误
+func articleTextMatch(field, query string) types.Query {
+	match := types.MatchQuery{Query: query, Fuzziness: "AUTO"}
+	if analyzer := elasticsearch.ArticleAnalyzer(field).Search; analyzer != "" {
+		match.Analyzer = &analyzer
+	}
+	return types.Query{Match: map[string]types.MatchQuery{field: match}}
+}
+
+// articleSearchRequest 根据搜索条件构建文章搜索请求
+func articleSearchRequest(info request.ArticleSearch) *search.Request {
 	// 创建一个 Elasticsearch 搜索请求对象
 	req := &search.Request{
 		Query: &types.Query{},
@@ -46,13 +90,28 @@ func (articleService *ArticleService) ArticleSearch(info request.ArticleSearch)
 	if info.Query != "" {
 		boolQuery.Should = []types.Query{
 			// 匹配文章标题
-			{Match: map[string]types.MatchQuery{"title": {Query: info.Query}}},
+			articleTextMatch("title", info.Query),
 			// 匹配文章关键词
 			{Match: map[string]types.MatchQuery{"keyword": {Query: info.Query}}},
 			// 匹配文章摘要
-			{Match: map[string]types.MatchQuery{"abstract": {Query: info.Query}}},
+			articleTextMatch("abstract", info.Query),
 			// 匹配文章内容
-			{Match: map[string]types.MatchQuery{"content": {Query: info.Query}}},
+			articleTextMatch("content", info.Query),
+		}
+		// 存在 Filter 子查询时 Should 子查询默认可选，需要至少匹配一个
+		boolQuery.MinimumShouldMatch = 1
+
+		// 高亮匹配的片段，标题和摘要返回完整内容，正文只返回部分片段
+		whole, fragments, fragmentSize := 0, 3, 100
+		req.Highlight = &types.Highlight{
+			Encoder:  &highlighterencoder.Html,
+			PreTags:  []string{"<em>"},
+			PostTags: []string{"</em>"},
+			Fields: map[string]types.HighlightField{
+				"title":    {NumberOfFragments: &whole},
+				"abstract": {NumberOfFragments: &whole},
+				"content":  {NumberOfFragments: &fragments, FragmentSize: &fragmentSize},
+			},
 		}
 	}
 
@@ -72,14 +131,9 @@ func (articleService *ArticleService) ArticleSearch(info request.ArticleSearch)
 		}
 	}
 
-	// 如果有查询条件，则使用 Bool 查询，否则使用 MatchAll 查询
-	// 如果 Bool 查询的 Should、Must 或 Filter 子查询不为空，则将 Bool 查询设置到请求中
-	if boolQuery.Should != nil || boolQuery.Must != nil || boolQuery.Filter != nil {
-		req.Query.Bool = boolQuery
-	} else {
-		// 若没有查询条件，则使用 MatchAll 查询，即返回所有文章
-		req.Query.MatchAll = &types.MatchAllQuery{}
-	}
+	// 只返回已发布的文章
+	boolQuery.Filter = append(boolQuery.Filter, publishedQuery())
+	req.Query.Bool = boolQuery
 
 	// 设置排序字段
 	// 如果传入了排序字段，则根据传入的排序规则设置排序方式
@@ -118,15 +172,7 @@ func (articleService *ArticleService) ArticleSearch(info request.ArticleSearch)
 		}
 	}
 
-	// 构建 Elasticsearch 查询选项
-	option := other.EsOption{
-		PageInfo:       info.PageInfo,                                                                                          // 分页信息
-		Index:          elasticsearch.ArticleIndex(),                                                                           // 要查询的 Elasticsearch 索引
-		Request:        req,                                                                                                    // Elasticsearch 请求对象
-		SourceIncludes: []string{"created_at", "cover", "title", "abstract", "category", "tags", "views", "comments", "likes"}, // 要返回的字段
-	}
-	// 调用工具函数进行 Elasticsearch 分页查询，并返回结果
-	return utils.EsPagination(context.TODO(), option)
+	return req
 }
 
 func (articleService *ArticleService) ArticleCategory() ([]database.ArticleCategory, error) {
@@ -146,7 +192,7 @@ func (articleService *ArticleService) ArticleTags() ([]database.ArticleTag, erro
 }
 
 func (articleService *ArticleService) ArticleLike(req request.ArticleLike) error {
-	return global.DB.Transaction(func(tx *gorm.DB) error {
+	return outboxTransaction(func(tx *gorm.DB) error {
 		var al database.ArticleLike
 		var num int
 
@@ -165,9 +211,7 @@ func (articleService *ArticleService) ArticleLike(req request.ArticleLike) error
 
 		// 更新文章收藏数
 		source := "ctx._source.likes += " + strconv.Itoa(num)
-		script := types.Script{Source: &source, Lang: &scriptlanguage.Painless}
-		_, err := global.ESClient.Update(elasticsearch.ArticleIndex(), req.ArticleID).Script(&script).Do(context.TODO())
-		return err
+		return ServiceGroupApp.OutboxService.Script(tx, elasticsearch.ArticleIndex(), req.ArticleID, source)
 	})
 }
 
@@ -196,9 +240,15 @@ func (articleService *ArticleService) ArticleLikesList(info request.ArticleLikes
 		if err != nil {
 			return nil, 0, err
 		}
+		// 已收藏的文章被撤回为草稿或归档时不再展示，总数仍按收藏记录计算，与分页保持一致
+		if !article.IsPublished() {
+			continue
+		}
 		article.UpdatedAt = ""
 		article.Keyword = ""
 		article.Content = ""
+		article.ContentHTML = ""
+		article.TOC = nil
 		list = append(list, struct {
 			Id_     string                `json:"_id"`
 			Source_ elasticsearch.Article `json:"_source"`
@@ -219,6 +269,20 @@ func (articleService *ArticleService) ArticleCreate(req request.ArticleCreate) e
 		return errors.New("the article already exists")
 	}
 	now := time.Now().Format("2006-01-02 15:04:05")
+	if req.Status == "" {
+		req.Status = appTypes.Published
+	}
+	publishAt, err := articleService.PublishTime(req.Status, req.PublishAt, "")
+	if err != nil {
+		return err
+	}
+	if req.Slug == "" {
+		req.Slug = req.Title
+	}
+	md, err := utils.RenderMarkdown(req.Content)
+	if err != nil {
+		return err
+	}
 	articleToCreate := elasticsearch.Article{
 		CreatedAt: now,
 		UpdatedAt: now,
@@ -229,8 +293,27 @@ func (articleService *ArticleService) ArticleCreate(req request.ArticleCreate) e
 		Tags:      req.Tags,
 		Abstract:  req.Abstract,
 		Content:   req.Content,
+
+		ContentHTML: md.HTML,
+		TOC:         md.TOC,
+		WordCount:   md.WordCount,
+		ReadingTime: md.ReadingTime,
+
+		Status:    req.Status,
+		PublishAt: publishAt,
 	}
-	return global.DB.Transaction(func(tx *gorm.DB) error {
+	id := uuid.Must(uuid.NewV4()).String()
+	return outboxTransaction(func(tx *gorm.DB) error {
+		// 在事务中检查并占用别名，并发创建的文章不会得到相同的别名
+		slug, err := articleService.UniqueSlug(tx, utils.Slugify(req.Slug), "")
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(&database.ArticleSlug{Slug: slug, ArticleID: id}).Error; err != nil {
+			return err
+		}
+		articleToCreate.Slug = slug
+
 		// 同时更新文章类别表中的数据
 		if err := articleService.UpdateCategoryCount(tx, "", articleToCreate.Category); err != nil {
 			return err
@@ -253,7 +336,19 @@ func (articleService *ArticleService) ArticleCreate(req request.ArticleCreate) e
 			return err
 		}
 
-		return articleService.Create(&articleToCreate)
+		if err := ServiceGroupApp.OutboxService.Index(tx, elasticsearch.ArticleIndex(), id, articleToCreate); err != nil {
+			return err
+		}
+
+		// 直接发布的文章通知订阅者
+		if articleToCreate.IsPublished() {
+			if err := ServiceGroupApp.NewsletterService.Announce(tx, id, articleToCreate); err != nil {
+				return err
+			}
+		}
+
+		// 保存文章的初始版本
+		return articleService.SaveRevision(tx, id, articleToCreate, req.UserID)
 	})
 }
 
@@ -261,8 +356,7 @@ func (articleService *ArticleService) ArticleDelete(req request.ArticleDelete) e
 	if len(req.IDs) == 0 {
 		return nil
 	}
-	return global.DB.Transaction(func(tx *gorm.DB) error {
-		commentService := new(CommentService)
+	return outboxTransaction(func(tx *gorm.DB) error {
 		for _, id := range req.IDs {
 			articleToDelete, err := articleService.Get(id)
 			if err != nil {
@@ -287,24 +381,26 @@ func (articleService *ArticleService) ArticleDelete(req request.ArticleDelete) e
 			if err := utils.InitImagesCategory(tx, illustrations); err != nil {
 				return err
 			}
-			// 同时删除该文章下的所有评论
-			comments, err := commentService.CommentInfoByArticleID(request.CommentInfoByArticleID{ArticleID: id})
-			if err != nil {
+			// 同时删除该文章下的所有评论，文章文档会被删除，不需要再更新评论数
+			if err := tx.Session(&gorm.Session{SkipHooks: true}).Where("article_id = ?", id).Delete(&database.Comment{}).Error; err != nil {
 				return err
 			}
-			// 同时删除该文章下的所有评论
-			// comments, err := ServiceGroupApp.CommentService.CommentInfoByArticleID(request.CommentInfoByArticleID{ArticleID: id})
-			// if err != nil {
-			// 	return err
-			// }
-			for _, comment := range comments {
-				if err := ServiceGroupApp.CommentService.DeleteCommentAndChildren(tx, comment.ID); err != nil {
-					return err
-				}
-			}
 		}
 
-		return articleService.Delete(req.IDs)
+		// 同时删除文章的别名和旧别名
+		if err := tx.Where("article_id IN ?", req.IDs).Delete(&database.ArticleSlug{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("article_id IN ?", req.IDs).Delete(&database.ArticleSlugRedirect{}).Error; err != nil {
+			return err
+		}
+
+		// 同时删除文章的修订记录
+		if err := tx.Where("article_id IN ?", req.IDs).Delete(&database.ArticleRevision{}).Error; err != nil {
+			return err
+		}
+
+		return ServiceGroupApp.OutboxService.Delete(tx, elasticsearch.ArticleIndex(), req.IDs...)
 	})
 }
 
@@ -314,11 +410,20 @@ func (articleService *ArticleService) ArticleUpdate(req request.ArticleUpdate) e
 		UpdatedAt string   `json:"updated_at"`
 		Cover     string   `json:"cover"`
 		Title     string   `json:"title"`
+		Slug      string   `json:"slug,omitempty"`
 		Keyword   string   `json:"keyword"`
 		Category  string   `json:"category"`
 		Tags      []string `json:"tags"`
 		Abstract  string   `json:"abstract"`
 		Content   string   `json:"content"`
+
+		ContentHTML string          `json:"content_html"`
+		TOC         []other.TOCItem `json:"toc"`
+		WordCount   int             `json:"word_count"`
+		ReadingTime int             `json:"reading_time"`
+
+		Status    appTypes.ArticleStatus `json:"status,omitempty"`
+		PublishAt string                 `json:"publish_at,omitempty"`
 	}{
 		UpdatedAt: now,
 		Cover:     req.Cover,
@@ -328,13 +433,44 @@ func (articleService *ArticleService) ArticleUpdate(req request.ArticleUpdate) e
 		Tags:      req.Tags,
 		Abstract:  req.Abstract,
 		Content:   req.Content,
+		Status:    req.Status,
+	}
+	md, err := utils.RenderMarkdown(req.Content)
+	if err != nil {
+		return err
 	}
-	return global.DB.Transaction(func(tx *gorm.DB) error {
+	articleToUpdate.ContentHTML, articleToUpdate.TOC, articleToUpdate.WordCount, articleToUpdate.ReadingTime = md.HTML, md.TOC, md.WordCount, md.ReadingTime
+	return outboxTransaction(func(tx *gorm.DB) error {
 		oldArticle, err := articleService.Get(req.ID)
 		if err != nil {
 			return err
 		}
 
+		// 修改别名时保留旧别名用于跳转，没有别名的旧文章根据标题生成
+		slug := utils.Slugify(req.Slug)
+		if slug == "" && oldArticle.Slug == "" {
+			slug = utils.Slugify(req.Title)
+		}
+		if slug != "" && slug != oldArticle.Slug {
+			if slug, err = articleService.UniqueSlug(tx, slug, req.ID); err != nil {
+				return err
+			}
+			if slug != oldArticle.Slug {
+				if err := articleService.ChangeSlug(tx, req.ID, oldArticle.Slug, slug); err != nil {
+					return err
+				}
+				articleToUpdate.Slug = slug
+			}
+		}
+
+		// 未指定状态时保持原状态，否则重新计算发布时间
+		if articleToUpdate.Status != "" {
+			articleToUpdate.PublishAt, err = articleService.PublishTime(articleToUpdate.Status, req.PublishAt, oldArticle.PublishAt)
+			if err != nil {
+				return err
+			}
+		}
+
 		// 同时更新文章类别表中的数据
 		if err := articleService.UpdateCategoryCount(tx, oldArticle.Category, articleToUpdate.Category); err != nil {
 			return err
@@ -370,7 +506,36 @@ func (articleService *ArticleService) ArticleUpdate(req request.ArticleUpdate) e
 			return err
 		}
 
-		return articleService.Update(req.ID, articleToUpdate)
+		// 历史文章没有修订记录时，先保存一份更新前的快照
+		if err := articleService.SaveInitialRevision(tx, req.ID, oldArticle); err != nil {
+			return err
+		}
+
+		if err := ServiceGroupApp.OutboxService.Update(tx, elasticsearch.ArticleIndex(), req.ID, articleToUpdate); err != nil {
+			return err
+		}
+
+		// 草稿或定时文章改为发布时通知订阅者
+		if articleToUpdate.Status == appTypes.Published && !oldArticle.IsPublished() {
+			if err := ServiceGroupApp.NewsletterService.Announce(tx, req.ID, elasticsearch.Article{
+				Title:    articleToUpdate.Title,
+				Category: articleToUpdate.Category,
+				Tags:     articleToUpdate.Tags,
+				Abstract: articleToUpdate.Abstract,
+			}); err != nil {
+				return err
+			}
+		}
+
+		// 保存更新后的版本
+		return articleService.SaveRevision(tx, req.ID, elasticsearch.Article{
+			Cover:    articleToUpdate.Cover,
+			Title:    articleToUpdate.Title,
+			Category: articleToUpdate.Category,
+			Tags:     articleToUpdate.Tags,
+			Abstract: articleToUpdate.Abstract,
+			Content:  articleToUpdate.Content,
+		}, req.UserID)
 	})
 }
 
@@ -402,6 +567,15 @@ func (articleService *ArticleService) ArticleList(info request.ArticleList) (lis
 		}
 	}
 
+	// 根据状态筛选
+	if info.Status != nil {
+		boolQuery.Filter = append(boolQuery.Filter, types.Query{
+			Term: map[string]types.TermQuery{
+				"status": {Value: *info.Status},
+			},
+		})
+	}
+
 	// 根据条件执行查询
 	if boolQuery.Must != nil || boolQuery.Filter != nil {
 		req.Query.Bool = boolQuery
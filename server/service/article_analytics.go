@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"gorm.io/gorm"
+	"server/global"
+	"server/model/appTypes"
+	"server/model/database"
+	"server/model/elasticsearch"
+	"server/model/request"
+	"server/model/response"
+	"time"
+)
+
+// statsMaxDays 按日期范围统计时最多统计的天数
+const statsMaxDays = 366
+
+// ErrStatsRangeInvalid 统计的日期范围无效
+var ErrStatsRangeInvalid = errors.New("统计的结束日期不能早于开始日期，且范围不能超过 366 天")
+
+// articleVisitRetention 访问事件的保留天数，汇总之后只需要保留最近的数据
+const articleVisitRetention = 30
+
+// articleReferrerCount 文章来源的访问次数
+type articleReferrerCount struct {
+	ArticleID string
+	Referrer  string
+	Number    int
+}
+
+// RollupArticleStats 将指定日期的访问事件、浏览量、收藏和评论汇总到每日统计表，重复执行会覆盖当天的汇总结果
+func (articleService *ArticleService) RollupArticleStats(day time.Time) error {
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.Local)
+	end := start.AddDate(0, 0, 1)
+	date := start.Format("2006-01-02")
+	inDay := global.DB.Where("created_at >= ? AND created_at < ?", start, end)
+
+	stats := make(map[string]*database.ArticleStatDaily)
+	stat := func(id string) *database.ArticleStatDaily {
+		if stats[id] == nil {
+			stats[id] = &database.ArticleStatDaily{ArticleID: id, Date: date}
+		}
+		return stats[id]
+	}
+
+	var views []database.ArticleViewDaily
+	if err := global.DB.Where("date = ?", date).Find(&views).Error; err != nil {
+		return err
+	}
+	for _, view := range views {
+		stat(view.ArticleID).Views = view.Views
+	}
+
+	var counts []articleCounter
+	if err := global.DB.Model(&database.ArticleVisit{}).Where(inDay).
+		Select("article_id, count(distinct visitor_id) AS number").Group("article_id").Scan(&counts).Error; err != nil {
+		return err
+	}
+	for _, count := range counts {
+		stat(count.ArticleID).Visitors = count.Number
+	}
+
+	counts = nil
+	if err := global.DB.Model(&database.ArticleLike{}).Where(inDay).
+		Select("article_id, count(*) AS number").Group("article_id").Scan(&counts).Error; err != nil {
+		return err
+	}
+	for _, count := range counts {
+		stat(count.ArticleID).Likes = count.Number
+	}
+
+	counts = nil
+	if err := global.DB.Model(&database.Comment{}).Where(inDay).Where("status = ? AND deleted = ?", appTypes.CommentApproved, false).
+		Select("article_id, count(*) AS number").Group("article_id").Scan(&counts).Error; err != nil {
+		return err
+	}
+	for _, count := range counts {
+		stat(count.ArticleID).Comments = count.Number
+	}
+
+	var referrerCounts []articleReferrerCount
+	if err := global.DB.Model(&database.ArticleVisit{}).Where(inDay).
+		Select("article_id, referrer, count(*) AS number").Group("article_id, referrer").Scan(&referrerCounts).Error; err != nil {
+		return err
+	}
+
+	return global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("date = ?", date).Delete(&database.ArticleStatDaily{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("date = ?", date).Delete(&database.ArticleReferrerDaily{}).Error; err != nil {
+			return err
+		}
+
+		rows := make([]database.ArticleStatDaily, 0, len(stats))
+		for _, s := range stats {
+			rows = append(rows, *s)
+		}
+		if len(rows) > 0 {
+			if err := tx.CreateInBatches(&rows, 500).Error; err != nil {
+				return err
+			}
+		}
+
+		referrers := make([]database.ArticleReferrerDaily, 0, len(referrerCounts))
+		for _, count := range referrerCounts {
+			referrers = append(referrers, database.ArticleReferrerDaily{
+				ArticleID: count.ArticleID,
+				Date:      date,
+				Referrer:  count.Referrer,
+				Visits:    count.Number,
+			})
+		}
+		if len(referrers) > 0 {
+			if err := tx.CreateInBatches(&referrers, 500).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// PurgeArticleVisits 删除超过保留天数的访问事件
+func (articleService *ArticleService) PurgeArticleVisits() error {
+	return global.DB.Where("created_at < ?", time.Now().AddDate(0, 0, -articleVisitRetention)).Delete(&database.ArticleVisit{}).Error
+}
+
+// statsDateRange 解析统计的起止日期，未指定日期范围时返回截止到今天的最近 date 天
+func statsDateRange(r request.StatsRange) (start, end time.Time, err error) {
+	if r.Start == "" {
+		now := time.Now()
+		end = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+		return end.AddDate(0, 0, -r.Date+1), end, nil
+	}
+
+	start, err = time.ParseInLocation("2006-01-02", r.Start, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, ErrStatsRangeInvalid
+	}
+	end, err = time.ParseInLocation("2006-01-02", r.End, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, ErrStatsRangeInvalid
+	}
+	if end.Before(start) || end.After(start.AddDate(0, 0, statsMaxDays-1)) {
+		return time.Time{}, time.Time{}, ErrStatsRangeInvalid
+	}
+	return start, end, nil
+}
+
+// ArticleStats 获取文章在日期范围内的每日浏览量、独立访客、收藏、评论以及主要来源
+func (articleService *ArticleService) ArticleStats(id string, req request.ArticleStats) (response.ArticleStats, error) {
+	var res response.ArticleStats
+
+	start, end, err := statsDateRange(req.StatsRange)
+	if err != nil {
+		return response.ArticleStats{}, err
+	}
+
+	// 生成日期列表
+	for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+		res.DateList = append(res.DateList, date.Format("2006-01-02"))
+	}
+	startDate, endDate := res.DateList[0], res.DateList[len(res.DateList)-1]
+
+	var rows []database.ArticleStatDaily
+	if err := global.DB.Model(&database.ArticleStatDaily{}).
+		Select("article_id, date_format(date, '%Y-%m-%d') AS date, views, visitors, likes, comments").
+		Where("article_id = ? AND date BETWEEN ? AND ?", id, startDate, endDate).Scan(&rows).Error; err != nil {
+		return response.ArticleStats{}, err
+	}
+	dateStats := make(map[string]database.ArticleStatDaily, len(rows))
+	for _, row := range rows {
+		dateStats[row.Date] = row
+	}
+
+	for _, date := range res.DateList {
+		stat := dateStats[date]
+		res.ViewData = append(res.ViewData, stat.Views)
+		res.VisitorData = append(res.VisitorData, stat.Visitors)
+		res.LikeData = append(res.LikeData, stat.Likes)
+		res.CommentData = append(res.CommentData, stat.Comments)
+	}
+
+	if err := global.DB.Model(&database.ArticleReferrerDaily{}).
+		Select("referrer, sum(visits) AS visits").
+		Where("article_id = ? AND date BETWEEN ? AND ?", id, startDate, endDate).
+		Group("referrer").Order("visits desc").Limit(10).Scan(&res.Referrers).Error; err != nil {
+		return response.ArticleStats{}, err
+	}
+	return res, nil
+}
+
+// ArticleStatsTop 获取日期范围内指定指标最高的文章
+func (articleService *ArticleService) ArticleStatsTop(req request.ArticleStatsTop) ([]response.ArticleStatsTop, error) {
+	size := req.Size
+	if size <= 0 || size > 50 {
+		size = 10
+	}
+	start, end, err := statsDateRange(req.StatsRange)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []response.ArticleStatsTop
+	if err := global.DB.Model(&database.ArticleStatDaily{}).
+		Select("article_id, sum(views) AS views, sum(visitors) AS visitors, sum(likes) AS likes, sum(comments) AS comments").
+		Where("date BETWEEN ? AND ?", start.Format("2006-01-02"), end.Format("2006-01-02")).
+		Group("article_id").Order(req.Metric + " desc").Limit(size).Scan(&list).Error; err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return list, nil
+	}
+
+	titles, err := articleService.titles(list)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		list[i].Title = titles[list[i].ArticleID]
+	}
+	return list, nil
+}
+
+// titles 获取文章的标题，已删除的文章没有标题
+func (articleService *ArticleService) titles(list []response.ArticleStatsTop) (map[string]string, error) {
+	ids := make([]string, 0, len(list))
+	for _, item := range list {
+		ids = append(ids, item.ArticleID)
+	}
+	res, err := global.ESClient.Search().
+		Index(elasticsearch.ArticleIndex()).
+		Query(&types.Query{Ids: &types.IdsQuery{Values: ids}}).
+		SourceIncludes_("title").
+		Size(len(ids)).
+		Do(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	titles := make(map[string]string, len(res.Hits.Hits))
+	for _, hit := range res.Hits.Hits {
+		var article elasticsearch.Article
+		if err := json.Unmarshal(hit.Source_, &article); err != nil {
+			return nil, err
+		}
+		titles[*hit.Id_] = article.Title
+	}
+	return titles, nil
+}
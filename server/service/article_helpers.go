@@ -5,37 +5,17 @@ import (
 	"encoding/json"
 	"errors"
 	"server/global"
+	"server/model/appTypes"
 	"server/model/database"
 	"server/model/elasticsearch"
 	"server/utils"
+	"time"
 
-	"github.com/elastic/go-elasticsearch/v8/typedapi/core/bulk"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
-	"github.com/elastic/go-elasticsearch/v8/typedapi/core/update"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
-	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/refresh"
 	"gorm.io/gorm"
 )
 
-// Create 用于将文章创建到 Elasticsearch
-func (articleService *ArticleService) Create(a *elasticsearch.Article) error {
-	// 将文章索引到Elasticsearch中，并设置刷新操作为 true
-	_, err := global.ESClient.Index(elasticsearch.ArticleIndex()).Request(a).Refresh(refresh.True).Do(context.TODO())
-	return err
-}
-
-// Delete 用于删除 Elasticsearch 中的文章
-func (articleService *ArticleService) Delete(ids []string) error {
-	var request bulk.Request
-	// 遍历文章ID，构建批量删除请求
-	for _, id := range ids {
-		request = append(request, types.OperationContainer{Delete: &types.DeleteOperation{Id_: &id}})
-	}
-	// 执行批量删除请求，并设置刷新操作为 true
-	_, err := global.ESClient.Bulk().Request(&request).Index(elasticsearch.ArticleIndex()).Refresh(refresh.True).Do(context.TODO())
-	return err
-}
-
 // Get 用于通过ID从 Elasticsearch 获取文章
 func (articleService *ArticleService) Get(id string) (elasticsearch.Article, error) {
 	var a elasticsearch.Article
@@ -53,18 +33,6 @@ func (articleService *ArticleService) Get(id string) (elasticsearch.Article, err
 	return a, err
 }
 
-// Update 用于更新文章数据
-func (articleService *ArticleService) Update(articleID string, v any) error {
-	// 将待更新的值转换为 JSON
-	bytes, err := json.Marshal(v)
-	if err != nil {
-		return err
-	}
-	// 执行更新请求，并设置刷新操作为 true
-	_, err = global.ESClient.Update(elasticsearch.ArticleIndex(), articleID).Request(&update.Request{Doc: bytes}).Refresh(refresh.True).Do(context.TODO())
-	return err
-}
-
 // Exits 用于检查文章标题是否存在
 func (articleService *ArticleService) Exits(title string) (bool, error) {
 	// 创建查询请求，匹配标题字段
@@ -159,4 +127,41 @@ func (articleService *ArticleService) UpdateTagsCount(tx *gorm.DB, oldTags, newT
 		}
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// PublishTime 根据文章状态计算发布时间
+// 定时发布的文章必须指定一个未来的发布时间；已发布的文章沿用原发布时间，没有则取当前时间；草稿和归档保持原发布时间不变
+func (articleService *ArticleService) PublishTime(status appTypes.ArticleStatus, publishAt, oldPublishAt string) (string, error) {
+	now := time.Now()
+	switch status {
+	case appTypes.Scheduled:
+		t, err := time.ParseInLocation("2006-01-02 15:04:05", publishAt, time.Local)
+		if err != nil {
+			return "", errors.New("invalid publish time, the format should be 2006-01-02 15:04:05")
+		}
+		if !t.After(now) {
+			return "", errors.New("the publish time of a scheduled article must be in the future")
+		}
+		return t.Format("2006-01-02 15:04:05"), nil
+	case appTypes.Published:
+		if oldPublishAt != "" && oldPublishAt <= now.Format("2006-01-02 15:04:05") {
+			return oldPublishAt, nil
+		}
+		return now.Format("2006-01-02 15:04:05"), nil
+	default:
+		return oldPublishAt, nil
+	}
+}
+
+// publishedQuery 构建只匹配已发布文章的查询条件，没有状态字段的旧文章同样视为已发布
+func publishedQuery() types.Query {
+	return types.Query{
+		Bool: &types.BoolQuery{
+			Should: []types.Query{
+				{Term: map[string]types.TermQuery{"status": {Value: appTypes.Published}}},
+				{Bool: &types.BoolQuery{MustNot: []types.Query{{Exists: &types.ExistsQuery{Field: "status"}}}}},
+			},
+			MinimumShouldMatch: 1,
+		},
+	}
+}
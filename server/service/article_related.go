@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/go-redis/redis"
+	"server/global"
+	"server/model/elasticsearch"
+	"server/model/response"
+	"time"
+)
+
+const (
+	relatedSize       = 6                         // 相关文章数量
+	relatedTTL        = time.Hour                 // 相关文章缓存时间
+	relatedVersionKey = "article-related-version" // 相关文章缓存的版本，文章索引变化后递增
+)
+
+// relatedKey 相关文章在 Redis 中的缓存键，包含缓存版本，版本递增后旧的缓存不再使用，等待过期
+func relatedKey(version, id string) string {
+	return "article-related-" + version + "-" + id
+}
+
+// ArticleRelated 获取与指定文章相似的文章，结果缓存在 Redis 中
+func (articleService *ArticleService) ArticleRelated(id string) ([]response.ArticleRelated, error) {
+	version, err := global.Redis.Get(relatedVersionKey).Result()
+	if errors.Is(err, redis.Nil) {
+		version = "0"
+	} else if err != nil {
+		return nil, err
+	}
+
+	result, err := global.Redis.Get(relatedKey(version, id)).Result()
+	if err == nil {
+		var list []response.ArticleRelated
+		if err := json.Unmarshal([]byte(result), &list); err == nil {
+			return list, nil
+		}
+	}
+
+	list, err := articleService.SearchRelated(id)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(list)
+	if err != nil {
+		return nil, err
+	}
+	if err := global.Redis.Set(relatedKey(version, id), data, relatedTTL).Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// SearchRelated 使用 more_like_this 查询相似的文章，标签相同或类别相同的文章优先
+func (articleService *ArticleService) SearchRelated(id string) ([]response.ArticleRelated, error) {
+	article, err := articleService.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if !article.IsPublished() {
+		return nil, errors.New("document not found")
+	}
+
+	index := elasticsearch.ArticleIndex()
+	minTermFreq, minDocFreq, maxQueryTerms := 1, 1, 25
+	tagBoost, categoryBoost := float32(2), float32(1.5)
+
+	boolQuery := &types.BoolQuery{
+		Must: []types.Query{
+			{MoreLikeThis: &types.MoreLikeThisQuery{
+				Fields:        []string{"title", "abstract", "content"},
+				Like:          []types.Like{types.LikeDocument{Index_: &index, Id_: &id}},
+				MinTermFreq:   &minTermFreq,
+				MinDocFreq:    &minDocFreq,
+				MaxQueryTerms: &maxQueryTerms,
+			}},
+		},
+		MustNot: []types.Query{
+			{Ids: &types.IdsQuery{Values: []string{id}}},
+		},
+		Filter: []types.Query{publishedQuery()},
+	}
+	for _, tag := range article.Tags {
+		boolQuery.Should = append(boolQuery.Should, types.Query{
+			Match: map[string]types.MatchQuery{"tags": {Query: tag, Boost: &tagBoost}},
+		})
+	}
+	if article.Category != "" {
+		boolQuery.Should = append(boolQuery.Should, types.Query{
+			Term: map[string]types.TermQuery{"category": {Value: article.Category, Boost: &categoryBoost}},
+		})
+	}
+
+	size := relatedSize
+	res, err := global.ESClient.Search().
+		Index(index).
+		Request(&search.Request{Query: &types.Query{Bool: boolQuery}, Size: &size}).
+		SourceIncludes_("created_at", "cover", "title", "slug", "abstract", "category", "tags", "views", "comments", "likes").
+		Do(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	list := []response.ArticleRelated{}
+	for _, hit := range res.Hits.Hits {
+		var a elasticsearch.Article
+		if err := json.Unmarshal(hit.Source_, &a); err != nil {
+			return nil, err
+		}
+		list = append(list, response.ArticleRelated{ID: *hit.Id_, Article: a})
+	}
+	return list, nil
+}
+
+// ClearRelated 使所有文章的相关文章缓存失效，文章的变化会影响其他文章的相关文章，因此不只清除单篇文章的缓存
+// 由发件箱在文章索引的变化投递到 ES 并刷新后调用，避免在投递之前重新缓存旧的结果
+func (articleService *ArticleService) ClearRelated() error {
+	return global.Redis.Incr(relatedVersionKey).Err()
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"server/global"
+	"server/model/database"
+	"server/model/elasticsearch"
+	"server/model/other"
+	"server/model/request"
+	"server/model/response"
+	"server/utils"
+	"strings"
+)
+
+// ArticleRevisionList 获取文章的修订记录列表，最新的版本在前
+func (articleService *ArticleService) ArticleRevisionList(info request.ArticleRevisionList) (interface{}, int64, error) {
+	db := global.DB.Where("article_id = ?", info.ArticleID)
+	option := other.MySQLOption{
+		PageInfo: info.PageInfo,
+		Where:    db,
+	}
+	return utils.MySQLPagination(&database.ArticleRevision{}, option)
+}
+
+// ArticleRevisionDiff 比较同一篇文章的两个版本，返回各字段的行级差异
+func (articleService *ArticleService) ArticleRevisionDiff(req request.ArticleRevisionDiff) (response.ArticleRevisionDiff, error) {
+	var from, to database.ArticleRevision
+	if err := global.DB.Take(&from, req.From).Error; err != nil {
+		return response.ArticleRevisionDiff{}, err
+	}
+	if err := global.DB.Take(&to, req.To).Error; err != nil {
+		return response.ArticleRevisionDiff{}, err
+	}
+	if from.ArticleID != to.ArticleID {
+		return response.ArticleRevisionDiff{}, errors.New("the revisions do not belong to the same article")
+	}
+
+	return response.ArticleRevisionDiff{
+		From:     from,
+		To:       to,
+		Title:    utils.DiffLines(from.Title, to.Title),
+		Abstract: utils.DiffLines(from.Abstract, to.Abstract),
+		Content:  utils.DiffLines(from.Content, to.Content),
+		Category: utils.DiffLines(from.Category, to.Category),
+		Cover:    utils.DiffLines(from.Cover, to.Cover),
+		Tags:     utils.DiffLines(strings.Join(from.Tags, "\n"), strings.Join(to.Tags, "\n")),
+	}, nil
+}
+
+// ArticleRevisionRestore 将文章恢复到指定版本，恢复操作本身会作为一个新版本保存
+func (articleService *ArticleService) ArticleRevisionRestore(req request.ArticleRevisionRestore) error {
+	var revision database.ArticleRevision
+	if err := global.DB.Take(&revision, req.ID).Error; err != nil {
+		return err
+	}
+
+	// 复用文章更新流程，保证类别、标签计数和图片类别同步变化
+	return articleService.ArticleUpdate(request.ArticleUpdate{
+		UserID:   req.UserID,
+		ID:       revision.ArticleID,
+		Cover:    revision.Cover,
+		Title:    revision.Title,
+		Category: revision.Category,
+		Tags:     revision.Tags,
+		Abstract: revision.Abstract,
+		Content:  revision.Content,
+	})
+}
+
+// SaveRevision 保存文章的一个版本
+func (articleService *ArticleService) SaveRevision(tx *gorm.DB, articleID string, a elasticsearch.Article, editorID uint) error {
+	return tx.Create(&database.ArticleRevision{
+		ArticleID: articleID,
+		Title:     a.Title,
+		Abstract:  a.Abstract,
+		Content:   a.Content,
+		Tags:      a.Tags,
+		Category:  a.Category,
+		Cover:     a.Cover,
+		EditorID:  editorID,
+	}).Error
+}
+
+// SaveInitialRevision 文章还没有任何修订记录时，保存其当前内容作为第一个版本
+func (articleService *ArticleService) SaveInitialRevision(tx *gorm.DB, articleID string, a elasticsearch.Article) error {
+	var count int64
+	if err := tx.Model(&database.ArticleRevision{}).Where("article_id = ?", articleID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	return articleService.SaveRevision(tx, articleID, a, 0)
+}
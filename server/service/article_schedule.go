@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"gorm.io/gorm"
+	"server/global"
+	"server/model/appTypes"
+	"server/model/elasticsearch"
+	"time"
+)
+
+// scheduledBatchSize 每次最多发布的定时文章数
+const scheduledBatchSize = 100
+
+// PublishScheduled 将到达发布时间的定时文章切换为已发布状态，并通知订阅者
+func (articleService *ArticleService) PublishScheduled() error {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	query := &types.Query{
+		Bool: &types.BoolQuery{
+			Filter: []types.Query{
+				{Term: map[string]types.TermQuery{"status": {Value: appTypes.Scheduled}}},
+				{Range: map[string]types.RangeQuery{"publish_at": types.DateRangeQuery{Lte: &now}}},
+			},
+		},
+	}
+
+	// 先找出要发布的文章，发布后用于通知订阅者
+	size := scheduledBatchSize
+	res, err := global.ESClient.Search().
+		Index(elasticsearch.ArticleIndex()).
+		Request(&search.Request{Query: query, Size: &size}).
+		SourceIncludes_("title", "slug", "category", "tags", "abstract").
+		Do(context.TODO())
+	if err != nil {
+		return err
+	}
+	if len(res.Hits.Hits) == 0 {
+		return nil
+	}
+
+	// 通过发件箱更新状态，重建索引期间的发布不会丢失，投递时再次检查状态，避免覆盖期间修改过的文章
+	source := "if (ctx._source.status == '" + string(appTypes.Scheduled) + "') { ctx._source.status = '" + string(appTypes.Published) + "' } else { ctx.op = 'noop' }"
+	return outboxTransaction(func(tx *gorm.DB) error {
+		for _, hit := range res.Hits.Hits {
+			var a elasticsearch.Article
+			if err := json.Unmarshal(hit.Source_, &a); err != nil {
+				return err
+			}
+			if err := ServiceGroupApp.OutboxService.Script(tx, elasticsearch.ArticleIndex(), *hit.Id_, source); err != nil {
+				return err
+			}
+			if err := ServiceGroupApp.NewsletterService.Announce(tx, *hit.Id_, a); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"server/config"
+	"server/global"
+	"server/model/request"
+	"testing"
+)
+
+func TestArticleSearchRequestAnalyzers(t *testing.T) {
+	tests := []struct {
+		name      string
+		analyzers map[string]config.ESAnalyzer
+		// 字段名到期望的搜索分词器，空字符串表示使用映射中的分词器
+		want map[string]string
+	}{
+		{
+			name: "default analyzer",
+			want: map[string]string{"title": "", "abstract": "", "content": ""},
+		},
+		{
+			name: "ik",
+			analyzers: map[string]config.ESAnalyzer{
+				"title":    {Index: "ik_max_word", Search: "ik_smart"},
+				"abstract": {Index: "ik_max_word", Search: "ik_smart"},
+				"content":  {Index: "ik_max_word"},
+			},
+			want: map[string]string{"title": "ik_smart", "abstract": "ik_smart", "content": "ik_max_word"},
+		},
+		{
+			name: "only some fields configured",
+			analyzers: map[string]config.ESAnalyzer{
+				"title": {Index: "smartcn"},
+			},
+			want: map[string]string{"title": "smartcn", "abstract": "", "content": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldConfig := global.Config
+			t.Cleanup(func() { global.Config = oldConfig })
+			global.Config = &config.Config{ES: config.ES{Analyzers: tt.analyzers}}
+
+			query := "Go 语言并发"
+			req := articleSearchRequest(request.ArticleSearch{Query: query})
+			got := make(map[string]string)
+			for _, q := range req.Query.Bool.Should {
+				for field, match := range q.Match {
+					if match.Query != query {
+						t.Errorf("%s query = %q, want %q", field, match.Query, query)
+					}
+					if field == "keyword" {
+						if match.Analyzer != nil || match.Fuzziness != nil {
+							t.Errorf("keyword match = %+v, want an exact match", match)
+						}
+						continue
+					}
+					if match.Fuzziness != "AUTO" {
+						t.Errorf("%s fuzziness = %v, want AUTO", field, match.Fuzziness)
+					}
+					got[field] = ""
+					if match.Analyzer != nil {
+						got[field] = *match.Analyzer
+					}
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("text clauses = %v, want %v", got, tt.want)
+			}
+			for field, want := range tt.want {
+				if got[field] != want {
+					t.Errorf("%s analyzer = %q, want %q", field, got[field], want)
+				}
+			}
+		})
+	}
+}
+
+func TestArticleSearchRequestFilters(t *testing.T) {
+	oldConfig := global.Config
+	t.Cleanup(func() { global.Config = oldConfig })
+	global.Config = &config.Config{}
+
+	req := articleSearchRequest(request.ArticleSearch{Query: "golang", Category: "后端", Tag: "Go"})
+	boolQuery := req.Query.Bool
+	if boolQuery.MinimumShouldMatch != 1 {
+		t.Errorf("minimum_should_match = %v, want 1", boolQuery.MinimumShouldMatch)
+	}
+	if len(boolQuery.Must) != 1 || boolQuery.Must[0].Match["tags"].Query != "Go" {
+		t.Errorf("tag clause = %+v, want a match on tags", boolQuery.Must)
+	}
+	if len(boolQuery.Filter) != 2 || boolQuery.Filter[0].Term["category"].Value != "后端" || boolQuery.Filter[1].Bool == nil {
+		t.Errorf("filter = %+v, want the category term and the published filter", boolQuery.Filter)
+	}
+	if req.Highlight == nil {
+		t.Fatal("highlight is missing")
+	}
+	for _, field := range []string{"title", "abstract", "content"} {
+		if _, ok := req.Highlight.Fields[field]; !ok {
+			t.Errorf("%s is not highlighted", field)
+		}
+	}
+}
+
+func TestArticleSearchRequestWithoutQuery(t *testing.T) {
+	req := articleSearchRequest(request.ArticleSearch{Sort: "view", Order: "desc"})
+	if len(req.Query.Bool.Should) != 0 || req.Query.Bool.MinimumShouldMatch != nil {
+		t.Errorf("should = %+v, want no text clauses", req.Query.Bool.Should)
+	}
+	if req.Highlight != nil {
+		t.Error("highlight should be omitted without a query")
+	}
+	if len(req.Query.Bool.Filter) != 1 {
+		t.Errorf("filter = %+v, want only the published filter", req.Query.Bool.Filter)
+	}
+	if len(req.Sort) != 1 {
+		t.Errorf("sort = %+v, want one sort on views", req.Sort)
+	}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"net/url"
+	"server/global"
+	"server/model/database"
+	"server/model/elasticsearch"
+	"server/model/other"
+	"server/model/response"
+	"server/utils"
+)
+
+// ArticleInfoBySlug 根据别名获取文章内容，旧别名会被解析到文章当前的别名
+func (articleService *ArticleService) ArticleInfoBySlug(slug string, visitor other.Visitor) (response.ArticleInfoBySlug, error) {
+	id, err := articleService.FindIDBySlug(slug)
+	if err != nil {
+		return response.ArticleInfoBySlug{}, err
+	}
+
+	// 当前别名中不存在时，从旧别名中查找
+	redirected := false
+	if id == "" {
+		var redirect database.ArticleSlugRedirect
+		if err := global.DB.Where("slug = ?", slug).First(&redirect).Error; err != nil {
+			return response.ArticleInfoBySlug{}, err
+		}
+		id = redirect.ArticleID
+		redirected = true
+	}
+
+	article, err := articleService.ArticleInfoByID(id, visitor)
+	if err != nil {
+		return response.ArticleInfoBySlug{}, err
+	}
+	return response.ArticleInfoBySlug{
+		ID:         id,
+		Slug:       article.Slug,
+		Redirected: redirected,
+		Article:    article,
+	}, nil
+}
+
+// FindIDBySlug 根据当前别名查找文章 ID，不存在时返回空字符串
+func (articleService *ArticleService) FindIDBySlug(slug string) (string, error) {
+	var articleSlug database.ArticleSlug
+	err := global.DB.Where("slug = ?", slug).Take(&articleSlug).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return articleSlug.ArticleID, nil
+}
+
+// UniqueSlug 生成不与其他文章（包括其他文章的旧别名）冲突的别名，冲突时依次追加 -2、-3 等后缀
+// 在 MySQL 中检查，并发创建同一别名时别名表的主键保证只有一个事务能够提交
+func (articleService *ArticleService) UniqueSlug(tx *gorm.DB, slug, articleID string) (string, error) {
+	if slug == "" {
+		slug = "article"
+	}
+	for i := 1; ; i++ {
+		candidate := slug
+		if i > 1 {
+			candidate = fmt.Sprintf("%s-%d", slug, i)
+		}
+
+		var count int64
+		if err := tx.Model(&database.ArticleSlug{}).Where("slug = ? AND article_id <> ?", candidate, articleID).Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count > 0 {
+			continue
+		}
+		if err := tx.Model(&database.ArticleSlugRedirect{}).Where("slug = ? AND article_id <> ?", candidate, articleID).Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count > 0 {
+			continue
+		}
+		return candidate, nil
+	}
+}
+
+// ChangeSlug 记录文章别名的变化，旧别名保留为跳转链接
+func (articleService *ArticleService) ChangeSlug(tx *gorm.DB, articleID, oldSlug, newSlug string) error {
+	// 新别名曾是该文章的旧别名时，不再需要跳转
+	if err := tx.Where("slug = ? AND article_id = ?", newSlug, articleID).Delete(&database.ArticleSlugRedirect{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("article_id = ?", articleID).Delete(&database.ArticleSlug{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Create(&database.ArticleSlug{Slug: newSlug, ArticleID: articleID}).Error; err != nil {
+		return err
+	}
+	if oldSlug == "" {
+		return nil
+	}
+	return tx.Create(&database.ArticleSlugRedirect{Slug: oldSlug, ArticleID: articleID}).Error
+}
+
+// SyncSlugs 把 ES 中已有文章的别名写入别名表，已存在的别名保持不变，返回写入的别名数
+func (articleService *ArticleService) SyncSlugs() (int64, error) {
+	var total int64
+	query := types.Query{Exists: &types.ExistsQuery{Field: "slug"}}
+	err := utils.EsScroll(context.TODO(), elasticsearch.ArticleIndex(), &query, []string{"slug"}, func(hits []types.Hit) error {
+		var slugs []database.ArticleSlug
+		for _, hit := range hits {
+			var a elasticsearch.Article
+			if err := json.Unmarshal(hit.Source_, &a); err != nil {
+				return err
+			}
+			if a.Slug != "" {
+				slugs = append(slugs, database.ArticleSlug{Slug: a.Slug, ArticleID: *hit.Id_})
+			}
+		}
+		if len(slugs) == 0 {
+			return nil
+		}
+		result := global.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&slugs)
+		total += result.RowsAffected
+		return result.Error
+	})
+	return total, err
+}
+
+// articlePermalink 生成文章在前台的永久链接，有别名时使用别名，否则使用文章 ID
+func articlePermalink(siteURL, id, slug string) string {
+	if slug != "" {
+		return siteURL + "/article/s/" + url.PathEscape(slug)
+	}
+	return articleURL(siteURL, id)
+}
@@ -1,8 +1,31 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/bulk"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/scriptlanguage"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"net/http"
 	"server/global"
+	"server/model/database"
+	"server/model/elasticsearch"
+	"server/model/other"
+	"server/utils"
 	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	articleViewWindow       = 30 * time.Minute // 同一访客在该时间内重复浏览同一篇文章只计一次
+	articleViewFlushLock    = "article_views:flush"
+	articleViewFlushLockTTL = 5 * time.Minute
 )
 
 func (articleService *ArticleService) NewArticleView() CountDB {
@@ -11,30 +34,210 @@ func (articleService *ArticleService) NewArticleView() CountDB {
 	}
 }
 
+// CountDB 使用 Redis 哈希记录计数增量，字段为 ID|日期
 type CountDB struct {
 	Index string
 }
 
-// Set 在原有基础上加一
-func (c CountDB) Set(id string) error {
-	num, _ := global.Redis.HGet(c.Index, id).Int()
-	num++
-	err := global.Redis.HSet(c.Index, id, num).Err()
-	return err
+// Set 记录一次浏览，同一访客在时间窗口内的重复浏览会被忽略，返回本次浏览是否被计入
+func (c CountDB) Set(id, visitor string) (bool, error) {
+	ok, err := global.Redis.SetNX(c.Index+":"+id+":"+visitor, 1, articleViewWindow).Result()
+	if err != nil || !ok {
+		return false, err
+	}
+	return true, global.Redis.HIncrBy(c.Index, id+"|"+time.Now().Format("2006-01-02"), 1).Err()
 }
 
-// GetInfo 取出数据
-func (c CountDB) GetInfo() map[string]int {
-	var Info = map[string]int{}
-	maps := global.Redis.HGetAll(c.Index).Val()
-	for id, val := range maps {
+// countBatchField 处理中的哈希里记录批次 ID 的字段，同一批数据重试时使用相同的批次 ID，用于避免重复累加
+const countBatchField = "_batch"
+
+// Take 取出待同步的增量以及本批数据的批次 ID，数据会被原子地移动到处理中的键，同步成功的字段需调用 Done 删除
+// 上次同步失败时优先返回上次未处理完的数据
+func (c CountDB) Take() (map[string]int, string, error) {
+	processing := c.processingKey()
+	exists, err := global.Redis.Exists(processing).Result()
+	if err != nil {
+		return nil, "", err
+	}
+	if exists == 0 {
+		if exists, err = global.Redis.Exists(c.Index).Result(); err != nil || exists == 0 {
+			return nil, "", err
+		}
+		if err := global.Redis.Rename(c.Index, processing).Err(); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := global.Redis.HSetNX(processing, countBatchField, uuid.Must(uuid.NewV4()).String()).Err(); err != nil {
+		return nil, "", err
+	}
+
+	var info = map[string]int{}
+	var batch string
+	maps, err := global.Redis.HGetAll(processing).Result()
+	if err != nil {
+		return nil, "", err
+	}
+	for field, val := range maps {
+		if field == countBatchField {
+			batch = val
+			continue
+		}
 		num, _ := strconv.Atoi(val)
-		Info[id] = num
+		info[field] = num
 	}
-	return Info
+	return info, batch, nil
 }
 
-// Clear 清除数据
-func (c CountDB) Clear() {
-	global.Redis.Del(c.Index)
-}
\ No newline at end of file
+// Done 删除已同步的字段，所有字段都同步完成后删除处理中的键
+func (c CountDB) Done(fields ...string) error {
+	processing := c.processingKey()
+	if len(fields) > 0 {
+		if err := global.Redis.HDel(processing, fields...).Err(); err != nil {
+			return err
+		}
+	}
+	n, err := global.Redis.HLen(processing).Result()
+	if err != nil || n > 1 {
+		return err
+	}
+	return global.Redis.Del(processing).Err()
+}
+
+func (c CountDB) processingKey() string {
+	return c.Index + ":processing"
+}
+
+// RecordVisit 记录访客的一次浏览，计入浏览量的访问同时写入访问事件表
+func (articleService *ArticleService) RecordVisit(id string, visitor other.Visitor) error {
+	counted, err := articleService.NewArticleView().Set(id, visitor.ID)
+	if err != nil || !counted {
+		return err
+	}
+	return global.DB.Create(&database.ArticleVisit{
+		ArticleID: id,
+		VisitorID: visitor.ID,
+		Referrer:  visitor.Referrer,
+	}).Error
+}
+
+// FlushArticleViews 将 Redis 中的浏览量增量累加到每日浏览量表，再通过一次批量请求同步到 ES
+// 每批数据有唯一的批次 ID，重试时已经写入的每日浏览量和已经累加过的文章不会重复累加
+func (articleService *ArticleService) FlushArticleViews() error {
+	// 重建文章索引期间暂停同步，浏览量保留在 Redis 中，否则写入旧索引的浏览量会在切换后丢失
+	if running, err := ServiceGroupApp.EsService.ArticleReindexRunning(); err != nil || running {
+		return err
+	}
+	// 多实例部署时只有一个实例同步
+	unlock, ok, err := utils.Lock(articleViewFlushLock, articleViewFlushLockTTL)
+	if err != nil || !ok {
+		return err
+	}
+	defer unlock()
+
+	articleView := articleService.NewArticleView()
+	views, batch, err := articleView.Take()
+	if err != nil || len(views) == 0 {
+		if err == nil && batch != "" {
+			return articleView.Done()
+		}
+		return err
+	}
+
+	// 旧版本的字段只有文章 ID，计入当天
+	today := time.Now().Format("2006-01-02")
+	total := make(map[string]int)
+	fields := make(map[string][]string)
+	var daily []database.ArticleViewDaily
+	for field, num := range views {
+		id, date, found := strings.Cut(field, "|")
+		fields[id] = append(fields[id], field)
+		if num == 0 {
+			continue
+		}
+		if !found {
+			date = today
+		}
+		total[id] += num
+		daily = append(daily, database.ArticleViewDaily{ArticleID: id, Date: date, Views: num})
+	}
+
+	// 每日浏览量和批次记录在同一事务中写入，批次已存在时说明上次已经写入过
+	if err := global.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&database.ArticleViewFlush{Batch: batch})
+		if result.Error != nil || result.RowsAffected == 0 || len(daily) == 0 {
+			return result.Error
+		}
+		if err := tx.Where("created_at < ?", time.Now().AddDate(0, 0, -7)).Delete(&database.ArticleViewFlush{}).Error; err != nil {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "article_id"}, {Name: "date"}},
+			DoUpdates: clause.Assignments(map[string]any{"views": gorm.Expr("views + VALUES(views)")}),
+		}).Create(&daily).Error
+	}); err != nil {
+		return err
+	}
+
+	// 只删除已经同步到 ES 的文章，失败的文章保留到下次使用相同的批次 ID 重试
+	synced, err := articleService.bulkAddViews(total, batch)
+	var done []string
+	for _, id := range synced {
+		done = append(done, fields[id]...)
+	}
+	if doneErr := articleView.Done(done...); doneErr != nil {
+		return doneErr
+	}
+	return err
+}
+
+// bulkAddViews 通过一次批量请求增加多篇文章的浏览量，返回同步成功的文章，已删除的文章视为同步成功
+// 文章记录最近一次累加的批次 ID，同一批次重试时不会重复累加
+func (articleService *ArticleService) bulkAddViews(views map[string]int, batch string) ([]string, error) {
+	if len(views) == 0 {
+		return nil, nil
+	}
+	source := "if (ctx._source.views_batch == params.batch) { ctx.op = 'noop' } else { ctx._source.views += params.views; ctx._source.views_batch = params.batch }"
+	batchParam, err := json.Marshal(batch)
+	if err != nil {
+		return nil, err
+	}
+	var request bulk.Request
+	for id, num := range views {
+		request = append(request,
+			types.OperationContainer{Update: &types.UpdateOperation{Id_: &id}},
+			types.UpdateAction{Script: &types.Script{
+				Source: &source,
+				Lang:   &scriptlanguage.Painless,
+				Params: map[string]json.RawMessage{
+					"views": json.RawMessage(strconv.Itoa(num)),
+					"batch": batchParam,
+				},
+			}},
+		)
+	}
+	res, err := global.ESClient.Bulk().Index(elasticsearch.ArticleIndex()).Request(&request).Do(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	var synced []string
+	var failed int
+	for _, item := range res.Items {
+		for _, result := range item {
+			if result.Error != nil && result.Status != http.StatusNotFound {
+				failed++
+				global.Log.Error("Failed to update article views",
+					zap.Stringp("id", result.Id_),
+					zap.Stringp("reason", result.Error.Reason))
+				continue
+			}
+			if result.Id_ != nil {
+				synced = append(synced, *result.Id_)
+			}
+		}
+	}
+	if failed > 0 {
+		return synced, fmt.Errorf("failed to update views of %d articles", failed)
+	}
+	return synced, nil
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/textquerytype"
+	"server/global"
+	"server/model/elasticsearch"
+	"server/model/response"
+)
+
+// suggestSize 自动补全返回的标题数量
+const suggestSize = 8
+
+// ArticleSuggest 根据输入的前缀补全文章标题
+func (articleService *ArticleService) ArticleSuggest(query string) ([]response.ArticleSuggest, error) {
+	size := suggestSize
+	req := &search.Request{
+		Query: &types.Query{
+			Bool: &types.BoolQuery{
+				Must: []types.Query{
+					{MultiMatch: &types.MultiMatchQuery{
+						Query:  query,
+						Type:   &textquerytype.Boolprefix,
+						Fields: []string{"title.suggest", "title.suggest._2gram", "title.suggest._3gram"},
+					}},
+				},
+				Filter: []types.Query{publishedQuery()},
+			},
+		},
+		Size: &size,
+	}
+	res, err := global.ESClient.Search().
+		Index(elasticsearch.ArticleIndex()).
+		Request(req).
+		SourceIncludes_("title", "slug").
+		Do(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	list := []response.ArticleSuggest{}
+	for _, hit := range res.Hits.Hits {
+		var a elasticsearch.Article
+		if err := json.Unmarshal(hit.Source_, &a); err != nil {
+			return nil, err
+		}
+		list = append(list, response.ArticleSuggest{ID: *hit.Id_, Title: a.Title, Slug: a.Slug})
+	}
+	return list, nil
+}
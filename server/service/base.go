@@ -1,59 +1,131 @@
 package service
 
 import (
-	"github.com/gin-contrib/sessions"
-	"github.com/gin-gonic/gin"
+	"errors"
+	"github.com/go-redis/redis"
+	"gorm.io/gorm"
 	"server/global"
+	"server/model/appTypes"
+	"server/model/database"
 	"server/utils"
+	"strings"
 	"time"
 )
 
+const (
+	verificationCodeTTL     = 5 * time.Minute // 验证码有效期
+	verificationCooldown    = time.Minute     // 同一邮箱两次发送的最小间隔
+	verificationWindow      = time.Hour       // 发送次数的统计窗口
+	verificationEmailLimit  = 10              // 每个邮箱在统计窗口内最多发送的次数
+	verificationIPLimit     = 20              // 每个 IP 在统计窗口内最多发送的次数
+	verificationMaxAttempts = 5               // 验证码最多允许输错的次数，超过后作废
+)
+
+var (
+	ErrVerificationTooFrequent = errors.New("verification codes are requested too frequently, please try again later")
+	ErrVerificationCodeInvalid = errors.New("invalid verification code")
+	ErrVerificationCodeExpired = errors.New("the verification code has expired, please resend it")
+	ErrVerificationCodeLocked  = errors.New("too many failed attempts, please resend the verification code")
+)
+
+// consumeVerificationCode 原子地校验并消费验证码，返回 1 表示通过，0 表示错误，-1 表示不存在或已过期，-2 表示错误次数过多已作废
+var consumeVerificationCode = redis.NewScript(`
+local code = redis.call('HGET', KEYS[1], 'code')
+if not code then
+	return -1
+end
+if code == ARGV[1] then
+	redis.call('DEL', KEYS[1])
+	return 1
+end
+if redis.call('HINCRBY', KEYS[1], 'attempts', 1) >= tonumber(ARGV[2]) then
+	redis.call('DEL', KEYS[1])
+	return -2
+end
+return 0
+`)
+
 // BaseService 结构体用于封装基础服务的方法
 type BaseService struct {
 }
 
 // SendEmailVerificationCode 方法用于向指定邮箱发送邮箱验证码
-// 参数 c 是 Gin 框架的上下文对象，包含了请求和响应的相关信息
-// 参数 to 是要发送验证码的目标邮箱地址
-func (baseService *BaseService) SendEmailVerificationCode(c *gin.Context, to string) error {
-	// 调用 utils 包中的 GenerateVerificationCode 函数生成一个 6 位的验证码
+// 验证码按邮箱和用途保存在 Redis 中，同一邮箱和同一 IP 的发送次数都有限制
+func (baseService *BaseService) SendEmailVerificationCode(to string, purpose appTypes.VerificationPurpose, ip string) error {
+	to = strings.ToLower(to)
+
+	// 同一邮箱在冷却时间内只能发送一次
+	ok, err := global.Redis.SetNX("verification:cooldown:"+to, 1, verificationCooldown).Result()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrVerificationTooFrequent
+	}
+
+	// 限制每个邮箱和每个 IP 在统计窗口内的发送次数
+	for key, limit := range map[string]int64{
+		"verification:email:" + to: verificationEmailLimit,
+		"verification:ip:" + ip:    verificationIPLimit,
+	} {
+		count, err := utils.IncrWindow(key, verificationWindow)
+		if err != nil {
+			return err
+		}
+		if count > limit {
+			return ErrVerificationTooFrequent
+		}
+	}
+
+	// 找回密码时邮箱未注册则不发送，避免泄露邮箱是否注册
+	if purpose == appTypes.VerificationForgotPassword {
+		err := global.DB.Select("id").Where("email = ?", to).Take(&database.User{}).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// 调用 utils 包中的 GenerateVerificationCode 函数生成一个 6 位的验证码，新验证码会覆盖同一用途的旧验证码
 	verificationCode := utils.GenerateVerificationCode(6)
-	// 计算验证码的过期时间，当前时间加上 5 分钟
-	expireTime := time.Now().Add(5 * time.Minute).Unix()
-
-	// 获取当前请求的会话对象
-	session := sessions.Default(c)
-	// 将生成的验证码存入会话中，键名为 "verification_code"
-	session.Set("verification_code", verificationCode)
-	// 将目标邮箱地址存入会话中，键名为 "email"
-	session.Set("email", to)
-	// 将验证码的过期时间存入会话中，键名为 "expire_time"
-	session.Set("expire_time", expireTime)
-	// 将会话数据保存
-	_ = session.Save()
-
-	// 定义邮件的主题
-	subject := "您的邮箱验证码"
-	// 定义邮件的正文内容，包含验证码、过期提示等信息
-	body := `亲爱的用户[` + to + `]，<br/>
-<br/>
-感谢您注册` + global.Config.Website.Name + `的个人博客！为了确保您的邮箱安全，请使用以下验证码进行验证：<br/>
-<br/>
-验证码：[<font color="blue"><u>` + verificationCode + `</u></font>]<br/>
-该验证码在 5 分钟内有效，请尽快使用。<br/>
-<br/>
-如果您没有请求此验证码，请忽略此邮件。
-<br/>
-如有任何疑问，请联系我们的支持团队：<br/>
-邮箱：` + global.Config.Email.From + `<br/>
-<br/>
-祝好，<br/>` +
-		global.Config.Website.Title + `<br/>
-<br/>`
-
-	// 调用 utils 包中的 Email 函数发送邮件，传入目标邮箱、主题和正文内容
-	_ = utils.Email(to, subject, body)
-
-	// 返回 nil 表示发送邮件过程中没有出现错误
-	return nil
+	key := verificationKey(to, purpose)
+	pipe := global.Redis.TxPipeline()
+	pipe.Del(key)
+	pipe.HMSet(key, map[string]interface{}{"code": verificationCode, "attempts": 0})
+	pipe.Expire(key, verificationCodeTTL)
+	if _, err := pipe.Exec(); err != nil {
+		return err
+	}
+
+	// 使用验证码模板生成邮件，放入发送队列异步发送
+	return ServiceGroupApp.MailService.Send(to, MailVerificationCode, map[string]any{
+		"Email":   to,
+		"Code":    verificationCode,
+		"Minutes": int(verificationCodeTTL.Minutes()),
+	})
+}
+
+// VerifyEmailCode 校验邮箱验证码，验证码只能使用一次，输错次数过多后作废
+func (baseService *BaseService) VerifyEmailCode(email string, purpose appTypes.VerificationPurpose, code string) error {
+	result, err := consumeVerificationCode.Run(&global.Redis, []string{verificationKey(strings.ToLower(email), purpose)}, code, verificationMaxAttempts).Int()
+	if err != nil {
+		return err
+	}
+	switch result {
+	case 1:
+		return nil
+	case 0:
+		return ErrVerificationCodeInvalid
+	case -2:
+		return ErrVerificationCodeLocked
+	default:
+		return ErrVerificationCodeExpired
+	}
+}
+
+// verificationKey 验证码在 Redis 中的键，按用途和邮箱区分
+func verificationKey(email string, purpose appTypes.VerificationPurpose) string {
+	return "verification:code:" + string(purpose) + ":" + email
 }
@@ -4,68 +4,78 @@ import (
 	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 	"server/global"
 	"server/model/appTypes"
 	"server/model/database"
 	"server/model/other"
 	"server/model/request"
+	"server/model/response"
 	"server/utils"
-	"sync"
+	"server/utils/contentFilter"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type CommentService struct{}
 
-// CommentInfoByArticleID 获取文章评论列表（带子评论）
-func (cs *CommentService) CommentInfoByArticleID(req request.CommentInfoByArticleID) ([]database.Comment, error) {
-	var comments []database.Comment
+// ErrCommentEditExpired 超过允许编辑的时间
+var ErrCommentEditExpired = errors.New("评论发布时间过长，无法编辑")
 
-	// 使用事务确保数据一致性
-	err := global.DB.Transaction(func(tx *gorm.DB) error {
-		// 查找指定文章的一级评论
-		if err := tx.Where("article_id = ? AND p_id IS NULL", req.ArticleID).
+// CommentInfoByArticleID 按游标分页获取文章的一级评论，置顶评论在第一页最前面，子评论一次查询后在内存中组装
+func (cs *CommentService) CommentInfoByArticleID(req request.CommentInfoByArticleID) (response.CommentCursor, error) {
+	pageSize := req.PageSize
+	if pageSize <= 0 || pageSize > 50 {
+		pageSize = 10
+	}
+
+	var comments []database.Comment
+	roots := func() *gorm.DB {
+		return global.DB.Where("article_id = ? AND p_id IS NULL AND status = ?", req.ArticleID, appTypes.CommentApproved).
 			Preload("User", func(db *gorm.DB) *gorm.DB {
 				return db.Select("uuid, username, avatar, address, signature")
-			}).
-			Find(&comments).Error; err != nil {
-			return err
-		}
-
-		// 使用goroutine并行加载子评论
-		var wg sync.WaitGroup
-		errChan := make(chan error, len(comments))
-
-		for i := range comments {
-			wg.Add(1)
-			go func(comment *database.Comment) {
-				defer wg.Done()
-				if err := cs.loadChildren(comment); err != nil {
-					errChan <- err
-				}
-			}(&comments[i])
-		}
-
-		wg.Wait()
-		close(errChan)
+			})
+	}
 
-		for err := range errChan {
-			return err
+	// 置顶评论只在第一页返回
+	if req.Cursor == "" {
+		if err := roots().Where("pinned = ?", true).Order("id").Find(&comments).Error; err != nil {
+			return response.CommentCursor{}, err
 		}
+	}
 
-		return nil
-	})
-
+	db, err := commentCursorQuery(roots().Where("pinned = ?", false), req.Sort, req.Cursor)
 	if err != nil {
-		return nil, err
+		return response.CommentCursor{}, err
+	}
+	// 多查一条用于判断是否还有下一页
+	var page []database.Comment
+	if err := db.Limit(pageSize + 1).Find(&page).Error; err != nil {
+		return response.CommentCursor{}, err
 	}
 
-	return comments, nil
+	var res response.CommentCursor
+	if len(page) > pageSize {
+		page = page[:pageSize]
+		cursor := commentCursor(page[pageSize-1], req.Sort)
+		res.NextCursor = &cursor
+	}
+	comments = append(comments, page...)
+
+	if err := cs.attachChildren(comments); err != nil {
+		return response.CommentCursor{}, err
+	}
+	res.List = comments
+	return res, nil
 }
 
 // CommentNew 获取最新评论
 func (cs *CommentService) CommentNew() ([]database.Comment, error) {
 	var comments []database.Comment
-	err := global.DB.Order("created_at desc").Limit(5).
+	err := global.DB.Where("status = ? AND deleted = ?", appTypes.CommentApproved, false).Order("created_at desc").Limit(5).
 		Preload("User", func(db *gorm.DB) *gorm.DB {
 			return db.Select("uuid, username, avatar, address, signature")
 		}).
@@ -76,8 +86,8 @@ func (cs *CommentService) CommentNew() ([]database.Comment, error) {
 	return comments, nil
 }
 
-// CommentCreate 创建新评论
-func (cs *CommentService) CommentCreate(req request.CommentCreate) error {
+// CommentCreate 创建新评论，返回评论的审核状态
+func (cs *CommentService) CommentCreate(req request.CommentCreate) (appTypes.CommentStatus, error) {
 	comment := database.Comment{
 		ArticleID: req.ArticleID,
 		UserUUID:  req.UserUUID,
@@ -88,23 +98,171 @@ func (cs *CommentService) CommentCreate(req request.CommentCreate) error {
 	if req.PID != nil && *req.PID > 0 {
 		// 验证父评论是否存在
 		if exists, err := cs.commentExists(*req.PID); err != nil {
-			return err
+			return "", err
 		} else if !exists {
-			return errors.New("父评论不存在")
+			return "", errors.New("父评论不存在")
 		}
 		comment.PID = req.PID
 	}
 
-	return global.DB.Create(&comment).Error
+	status, err := cs.initialStatus(req)
+	if err != nil {
+		return "", err
+	}
+
+	// 管理员的评论不经过内容过滤
+	if req.RoleID != appTypes.Admin {
+		result, err := ServiceGroupApp.ContentFilterService.Check("comment", req.UserUUID, req.Content)
+		if err != nil {
+			return "", err
+		}
+		switch result.Action {
+		case appTypes.FilterReject:
+			return "", contentFilter.ErrRejected
+		case appTypes.FilterModerate:
+			status = appTypes.CommentPending
+		}
+		comment.Content = result.Content
+	}
+	comment.Status = status
+	comment.Notified = status == appTypes.CommentApproved
+
+	if err := outboxTransaction(func(tx *gorm.DB) error {
+		return tx.Create(&comment).Error
+	}); err != nil {
+		return "", err
+	}
+	if status == appTypes.CommentApproved {
+		cs.notifyComment(comment)
+		cs.publishComment(comment.ID)
+	}
+	return status, nil
+}
+
+// CommentModerate 批量审核评论，审核状态变化时同步更新文章的评论数
+func (cs *CommentService) CommentModerate(req request.CommentModerate) error {
+	var approved []database.Comment
+	err := outboxTransaction(func(tx *gorm.DB) error {
+		var comments []database.Comment
+		if err := tx.Select("id", "article_id", "p_id", "user_uuid", "content", "status", "notified").Where("id IN ? AND deleted = ?", req.IDs, false).Find(&comments).Error; err != nil {
+			return err
+		}
+
+		for _, comment := range comments {
+			if comment.Status == req.Status {
+				continue
+			}
+			updates := map[string]any{"status": req.Status}
+			if comment.Status == appTypes.CommentPending && req.Status == appTypes.CommentApproved {
+				approved = append(approved, comment)
+				// 只在第一次审核通过时发送通知，编辑后重新审核通过的评论已经通知过
+				updates["notified"] = true
+			}
+			if err := tx.Model(&comment).Updates(updates).Error; err != nil {
+				return err
+			}
+
+			var delta int
+			if comment.Status == appTypes.CommentApproved {
+				delta = -1
+			} else if req.Status == appTypes.CommentApproved {
+				delta = 1
+			}
+			if delta != 0 {
+				if err := tx.Create(database.NewCommentCountOutbox(comment.ArticleID, delta)).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, comment := range approved {
+		if !comment.Notified {
+			cs.notifyComment(comment)
+		}
+		cs.publishComment(comment.ID)
+	}
+	return nil
+}
+
+// CommentUpdate 编辑评论，只有作者可以在发布后的一段时间内编辑，编辑前的内容保存到编辑历史
+func (cs *CommentService) CommentUpdate(req request.CommentUpdate) error {
+	return outboxTransaction(func(tx *gorm.DB) error {
+		var comment database.Comment
+		if err := tx.Take(&comment, req.ID).Error; err != nil {
+			return err
+		}
+		if comment.UserUUID != req.UserUUID || comment.Deleted {
+			return errors.New("没有权限编辑此评论")
+		}
+		if window := global.Config.Comment.EditWindow; window > 0 && time.Since(comment.CreatedAt) > time.Duration(window)*time.Minute {
+			return ErrCommentEditExpired
+		}
+		if comment.Content == req.Content {
+			return nil
+		}
+
+		status := comment.Status
+		content := req.Content
+		if req.RoleID != appTypes.Admin {
+			result, err := ServiceGroupApp.ContentFilterService.Check("comment", req.UserUUID, req.Content)
+			if err != nil {
+				return err
+			}
+			switch result.Action {
+			case appTypes.FilterReject:
+				return contentFilter.ErrRejected
+			case appTypes.FilterModerate:
+				status = appTypes.CommentPending
+			}
+			content = result.Content
+		}
+
+		if err := tx.Create(&database.CommentEdit{CommentID: comment.ID, Content: comment.Content}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&comment).Updates(map[string]any{
+			"content":   content,
+			"status":    status,
+			"edited_at": time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+		// 编辑后需要重新审核的评论不再计入文章评论数
+		if comment.Status == appTypes.CommentApproved && status != appTypes.CommentApproved {
+			return tx.Create(database.NewCommentCountOutbox(comment.ArticleID, -1)).Error
+		}
+		return nil
+	})
 }
 
-// CommentDelete 删除评论
+// CommentHistory 获取评论的编辑历史，只有作者和管理员可以查看
+func (cs *CommentService) CommentHistory(req request.CommentHistory) ([]database.CommentEdit, error) {
+	var comment database.Comment
+	if err := global.DB.Select("id", "user_uuid").Take(&comment, req.ID).Error; err != nil {
+		return nil, err
+	}
+	if comment.UserUUID != req.UserUUID && req.RoleID != appTypes.Admin {
+		return nil, errors.New("没有权限查看此评论的编辑历史")
+	}
+
+	var edits []database.CommentEdit
+	if err := global.DB.Where("comment_id = ?", req.ID).Order("id desc").Find(&edits).Error; err != nil {
+		return nil, err
+	}
+	return edits, nil
+}
+
+// CommentDelete 删除评论，有回复的评论保留为占位
 func (cs *CommentService) CommentDelete(c *gin.Context, req request.CommentDelete) error {
 	if len(req.IDs) == 0 {
 		return nil
 	}
 
-	return global.DB.Transaction(func(tx *gorm.DB) error {
+	return outboxTransaction(func(tx *gorm.DB) error {
 		for _, id := range req.IDs {
 			var comment database.Comment
 			if err := tx.Take(&comment, id).Error; err != nil {
@@ -117,7 +275,7 @@ func (cs *CommentService) CommentDelete(c *gin.Context, req request.CommentDelet
 				return errors.New("没有权限删除此评论")
 			}
 
-			if err := cs.deleteCommentAndChildren(tx, id); err != nil {
+			if err := cs.removeComment(tx, comment); err != nil {
 				return err
 			}
 		}
@@ -129,7 +287,7 @@ func (cs *CommentService) CommentDelete(c *gin.Context, req request.CommentDelet
 func (cs *CommentService) CommentInfo(uuid uuid.UUID) ([]database.Comment, error) {
 	var rawComments []database.Comment
 	err := global.DB.Order("created_at desc").
-		Where("user_uuid = ?", uuid).
+		Where("user_uuid = ? AND deleted = ?", uuid, false).
 		Preload("User", func(db *gorm.DB) *gorm.DB {
 			return db.Select("uuid, username, avatar, address, signature")
 		}).
@@ -138,10 +296,8 @@ func (cs *CommentService) CommentInfo(uuid uuid.UUID) ([]database.Comment, error
 		return nil, err
 	}
 
-	for i := range rawComments {
-		if err := cs.loadChildren(&rawComments[i]); err != nil {
-			return nil, err
-		}
+	if err := cs.attachChildren(rawComments); err != nil {
+		return nil, err
 	}
 
 	// 评论去重
@@ -155,6 +311,103 @@ func (cs *CommentService) CommentInfo(uuid uuid.UUID) ([]database.Comment, error
 	return comments, nil
 }
 
+// CommentReact 回应评论，重复相同的回应会取消，不同的回应会替换原来的回应
+func (cs *CommentService) CommentReact(req request.CommentReact) error {
+	var comment database.Comment
+	var liked bool
+	err := global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND status = ? AND deleted = ?", req.CommentID, appTypes.CommentApproved, false).Take(&comment).Error; err != nil {
+			return err
+		}
+
+		var reaction database.CommentReaction
+		var likes int
+		err := tx.Where("comment_id = ? AND user_id = ?", req.CommentID, req.UserID).Take(&reaction).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if err := tx.Create(&database.CommentReaction{CommentID: req.CommentID, UserID: req.UserID, Reaction: req.Reaction}).Error; err != nil {
+				return err
+			}
+			if req.Reaction == appTypes.ReactionLike {
+				likes = 1
+			}
+		case err != nil:
+			return err
+		case reaction.Reaction == req.Reaction:
+			if err := tx.Delete(&reaction).Error; err != nil {
+				return err
+			}
+			if req.Reaction == appTypes.ReactionLike {
+				likes = -1
+			}
+		default:
+			if err := tx.Model(&reaction).Update("reaction", req.Reaction).Error; err != nil {
+				return err
+			}
+			if req.Reaction == appTypes.ReactionLike {
+				likes = 1
+			} else if reaction.Reaction == appTypes.ReactionLike {
+				likes = -1
+			}
+		}
+
+		if likes == 0 {
+			return nil
+		}
+		liked = likes > 0
+		return tx.Model(&database.Comment{}).Where("id = ?", req.CommentID).UpdateColumn("likes", gorm.Expr("likes + ?", likes)).Error
+	})
+	if err != nil || !liked {
+		return err
+	}
+
+	// 反复点赞同一条评论时，接收者还有未读的点赞通知就不再重复通知
+	var unread int64
+	if err := global.DB.Model(&database.Notification{}).
+		Where("user_uuid = ? AND actor_uuid = ? AND type = ? AND comment_id = ? AND is_read = ?",
+			comment.UserUUID, req.UserUUID, appTypes.NotificationCommentLike, comment.ID, false).
+		Count(&unread).Error; err != nil {
+		global.Log.Error("Failed to check comment like notifications:", zap.Error(err))
+		return nil
+	}
+	if unread > 0 {
+		return nil
+	}
+
+	if err := ServiceGroupApp.NotificationService.Notify(database.Notification{
+		UserUUID:  comment.UserUUID,
+		ActorUUID: req.UserUUID,
+		Type:      appTypes.NotificationCommentLike,
+		ArticleID: comment.ArticleID,
+		CommentID: &comment.ID,
+		Content:   notificationSnippet(comment.Content),
+	}); err != nil {
+		global.Log.Error("Failed to send comment like notification:", zap.Error(err))
+	}
+	return nil
+}
+
+// CommentPin 置顶或取消置顶一级评论，每篇文章只保留一条置顶评论
+func (cs *CommentService) CommentPin(req request.CommentPin) error {
+	return global.DB.Transaction(func(tx *gorm.DB) error {
+		var comment database.Comment
+		if err := tx.Take(&comment, req.ID).Error; err != nil {
+			return err
+		}
+		if comment.PID != nil {
+			return errors.New("只能置顶一级评论")
+		}
+
+		if *req.Pinned {
+			if err := tx.Model(&database.Comment{}).Where("article_id = ? AND pinned = ?", comment.ArticleID, true).
+				UpdateColumn("pinned", false).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&comment).UpdateColumn("pinned", *req.Pinned).Error
+	})
+}
+
 // CommentList 评论列表分页查询
 func (cs *CommentService) CommentList(info request.CommentList) (interface{}, int64, error) {
 	db := global.DB.Model(&database.Comment{})
@@ -171,6 +424,10 @@ func (cs *CommentService) CommentList(info request.CommentList) (interface{}, in
 		db = db.Where("content LIKE ?", "%"+*info.Content+"%")
 	}
 
+	if info.Status != nil {
+		db = db.Where("status = ?", *info.Status)
+	}
+
 	option := other.MySQLOption{
 		PageInfo: info.PageInfo,
 		Where:    db,
@@ -179,49 +436,229 @@ func (cs *CommentService) CommentList(info request.CommentList) (interface{}, in
 	return utils.MySQLPagination(&database.Comment{}, option)
 }
 
-// loadChildren 递归加载子评论（私有方法）
-func (cs *CommentService) loadChildren(comment *database.Comment) error {
-	if comment == nil {
+// attachChildren 通过递归查询一次取出这些评论下所有审核通过的回复，在内存中组装为评论树，同时附带各条评论的回应数（私有方法）
+// 超过最大层级的回复会被展开到最深一层，按发布顺序排列
+func (cs *CommentService) attachChildren(comments []database.Comment) error {
+	if len(comments) == 0 {
 		return nil
 	}
 
-	var children []database.Comment
-	err := global.DB.Where("p_id = ?", comment.ID).
+	ids := make([]uint, 0, len(comments))
+	for _, comment := range comments {
+		ids = append(ids, comment.ID)
+	}
+	var replies []database.Comment
+	if err := global.DB.Where(`id IN (
+			WITH RECURSIVE thread (id) AS (
+				SELECT id FROM comments WHERE p_id IN ? AND status = ? AND deleted_at IS NULL
+				UNION ALL
+				SELECT c.id FROM comments AS c JOIN thread AS t ON c.p_id = t.id WHERE c.status = ? AND c.deleted_at IS NULL
+			)
+			SELECT id FROM thread
+		)`, ids, appTypes.CommentApproved, appTypes.CommentApproved).
+		Order("id").
 		Preload("User", func(db *gorm.DB) *gorm.DB {
 			return db.Select("uuid, username, avatar, address, signature")
 		}).
-		Find(&children).Error
+		Find(&replies).Error; err != nil {
+		return err
+	}
 
-	if err != nil {
+	if err := cs.attachReactions(comments, replies); err != nil {
 		return err
 	}
+	hideDeleted(comments)
+	hideDeleted(replies)
 
-	for i := range children {
-		if err := cs.loadChildren(&children[i]); err != nil {
-			return err
+	childrenMap := make(map[uint][]database.Comment)
+	for _, reply := range replies {
+		childrenMap[*reply.PID] = append(childrenMap[*reply.PID], reply)
+	}
+
+	maxDepth := global.Config.Comment.MaxDepth
+	for i := range comments {
+		comments[i].Children = buildChildren(childrenMap, comments[i].ID, 1, maxDepth)
+	}
+	return nil
+}
+
+// attachReactions 一次查询出多组评论的回应数（私有方法）
+func (cs *CommentService) attachReactions(groups ...[]database.Comment) error {
+	var ids []uint
+	for _, group := range groups {
+		for _, comment := range group {
+			ids = append(ids, comment.ID)
 		}
 	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var counts []struct {
+		CommentID uint
+		Reaction  appTypes.CommentReaction
+		Number    int
+	}
+	if err := global.DB.Model(&database.CommentReaction{}).
+		Select("comment_id, reaction, count(*) AS number").
+		Where("comment_id IN ?", ids).
+		Group("comment_id, reaction").Scan(&counts).Error; err != nil {
+		return err
+	}
 
-	comment.Children = children
+	reactions := make(map[uint]map[appTypes.CommentReaction]int)
+	for _, count := range counts {
+		if reactions[count.CommentID] == nil {
+			reactions[count.CommentID] = make(map[appTypes.CommentReaction]int)
+		}
+		reactions[count.CommentID][count.Reaction] = count.Number
+	}
+	for _, group := range groups {
+		for i := range group {
+			group[i].Reactions = reactions[group[i].ID]
+		}
+	}
 	return nil
 }
 
-// deleteCommentAndChildren 递归删除评论及其子评论（私有方法）
-func (cs *CommentService) deleteCommentAndChildren(tx *gorm.DB, id uint) error {
-	// 先删除所有子评论
-	var childIDs []uint
-	if err := tx.Model(&database.Comment{}).Where("p_id = ?", id).Pluck("id", &childIDs).Error; err != nil {
+// notifyComment 通知被回复的评论作者和评论中 @ 到的用户，通知失败不影响评论本身（私有方法）
+func (cs *CommentService) notifyComment(comment database.Comment) {
+	notificationService := ServiceGroupApp.NotificationService
+	snippet := notificationSnippet(comment.Content)
+	notified := make(map[uuid.UUID]bool)
+
+	var notifications []database.Notification
+	if comment.PID != nil {
+		var parent database.Comment
+		if err := global.DB.Select("user_uuid").Take(&parent, *comment.PID).Error; err == nil {
+			notified[parent.UserUUID] = true
+			notifications = append(notifications, database.Notification{
+				UserUUID:  parent.UserUUID,
+				ActorUUID: comment.UserUUID,
+				Type:      appTypes.NotificationCommentReply,
+				ArticleID: comment.ArticleID,
+				CommentID: &comment.ID,
+				Content:   snippet,
+			})
+		}
+	}
+
+	users, err := notificationService.MentionedUsers(comment.Content)
+	if err != nil {
+		global.Log.Error("Failed to find mentioned users:", zap.Error(err))
+	}
+	for _, user := range users {
+		if notified[user.UUID] {
+			continue
+		}
+		notified[user.UUID] = true
+		notifications = append(notifications, database.Notification{
+			UserUUID:  user.UUID,
+			ActorUUID: comment.UserUUID,
+			Type:      appTypes.NotificationMention,
+			ArticleID: comment.ArticleID,
+			CommentID: &comment.ID,
+			Content:   snippet,
+		})
+	}
+
+	if err := notificationService.Notify(notifications...); err != nil {
+		global.Log.Error("Failed to send comment notifications:", zap.Error(err))
+	}
+}
+
+// publishComment 向正在浏览文章的客户端推送新评论
+func (cs *CommentService) publishComment(id uint) {
+	var comment database.Comment
+	err := global.DB.Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("uuid, username, avatar, address, signature")
+	}).Take(&comment, id).Error
+	if err != nil {
+		global.Log.Error("Failed to load comment for streaming:", zap.Error(err))
+		return
+	}
+	ServiceGroupApp.StreamService.Publish(StreamArticleChannel(comment.ArticleID), appTypes.StreamComment, comment)
+}
+
+// hideDeleted 占位评论不展示作者信息
+func hideDeleted(comments []database.Comment) {
+	for i := range comments {
+		if comments[i].Deleted {
+			comments[i].User = database.User{}
+			comments[i].UserUUID = uuid.Nil
+		}
+	}
+}
+
+// buildChildren 组装评论的子评论，depth 为当前评论的层级，maxDepth 为 0 时不限制层级
+func buildChildren(childrenMap map[uint][]database.Comment, id uint, depth, maxDepth int) []database.Comment {
+	children := childrenMap[id]
+	if len(children) == 0 {
+		return nil
+	}
+
+	// 已到最大层级时，所有后代评论展开在这一层
+	if maxDepth > 0 && depth+1 >= maxDepth {
+		var flat []database.Comment
+		queue := append([]database.Comment(nil), children...)
+		for len(queue) > 0 {
+			child := queue[0]
+			queue = queue[1:]
+			queue = append(queue, childrenMap[child.ID]...)
+			child.Children = nil
+			flat = append(flat, child)
+		}
+		sort.Slice(flat, func(i, j int) bool {
+			return flat[i].ID < flat[j].ID
+		})
+		return flat
+	}
+
+	result := make([]database.Comment, len(children))
+	for i, child := range children {
+		child.Children = buildChildren(childrenMap, child.ID, depth+1, maxDepth)
+		result[i] = child
+	}
+	return result
+}
+
+// removeComment 删除评论，有回复的评论保留为占位，删除后父评论如果是没有其他回复的占位也一并删除（私有方法）
+func (cs *CommentService) removeComment(tx *gorm.DB, comment database.Comment) error {
+	var replies int64
+	if err := tx.Model(&database.Comment{}).Where("p_id = ?", comment.ID).Count(&replies).Error; err != nil {
 		return err
 	}
 
-	for _, childID := range childIDs {
-		if err := cs.deleteCommentAndChildren(tx, childID); err != nil {
+	if replies > 0 {
+		if comment.Deleted {
+			return nil
+		}
+		if err := tx.Model(&comment).Updates(map[string]any{"deleted": true, "content": ""}).Error; err != nil {
 			return err
 		}
+		if comment.Status == appTypes.CommentApproved {
+			return tx.Create(database.NewCommentCountOutbox(comment.ArticleID, -1)).Error
+		}
+		return nil
 	}
 
-	// 最后删除当前评论
-	return tx.Delete(&database.Comment{}, id).Error
+	if err := tx.Delete(&database.Comment{MODEL: global.MODEL{ID: comment.ID}}).Error; err != nil {
+		return err
+	}
+	if comment.PID == nil {
+		return nil
+	}
+	var parent database.Comment
+	if err := tx.Take(&parent, *comment.PID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if !parent.Deleted {
+		return nil
+	}
+	return cs.removeComment(tx, parent)
 }
 
 // findChildCommentsIDByRootCommentUserUUID 查找子评论ID（私有方法）
@@ -241,12 +678,78 @@ func (cs *CommentService) traverseChildren(comment *database.Comment, idMap map[
 	}
 }
 
-// commentExists 检查评论是否存在（私有方法）
+// commentExists 检查审核通过的评论是否存在（私有方法）
 func (cs *CommentService) commentExists(id uint) (bool, error) {
 	var count int64
-	err := global.DB.Model(&database.Comment{}).Where("id = ?", id).Count(&count).Error
+	err := global.DB.Model(&database.Comment{}).Where("id = ? AND status = ? AND deleted = ?", id, appTypes.CommentApproved, false).Count(&count).Error
 	if err != nil {
 		return false, err
 	}
 	return count > 0, nil
 }
+
+// initialStatus 根据评论审核模式确定新评论的状态，管理员的评论不需要审核（私有方法）
+func (cs *CommentService) initialStatus(req request.CommentCreate) (appTypes.CommentStatus, error) {
+	if req.RoleID == appTypes.Admin {
+		return appTypes.CommentApproved, nil
+	}
+	switch global.Config.Comment.Moderation {
+	case appTypes.ModerationAll:
+		return appTypes.CommentPending, nil
+	case appTypes.ModerationFirst:
+		var count int64
+		if err := global.DB.Model(&database.Comment{}).
+			Where("user_uuid = ? AND status = ?", req.UserUUID, appTypes.CommentApproved).
+			Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return appTypes.CommentPending, nil
+		}
+	}
+	return appTypes.CommentApproved, nil
+}
+
+// commentCursorQuery 根据排序方式和游标构建一级评论的查询条件（私有方法）
+func commentCursorQuery(db *gorm.DB, order appTypes.CommentSort, cursor string) (*gorm.DB, error) {
+	switch order {
+	case appTypes.CommentNewest:
+		if cursor != "" {
+			id, err := strconv.ParseUint(cursor, 10, 64)
+			if err != nil {
+				return nil, errors.New("invalid cursor")
+			}
+			db = db.Where("id < ?", id)
+		}
+		return db.Order("id desc"), nil
+	case appTypes.CommentMostLiked:
+		// 游标格式为 点赞数_ID
+		if cursor != "" {
+			likesStr, idStr, found := strings.Cut(cursor, "_")
+			likes, err1 := strconv.Atoi(likesStr)
+			id, err2 := strconv.ParseUint(idStr, 10, 64)
+			if !found || err1 != nil || err2 != nil {
+				return nil, errors.New("invalid cursor")
+			}
+			db = db.Where("likes < ? OR (likes = ? AND id < ?)", likes, likes, id)
+		}
+		return db.Order("likes desc, id desc"), nil
+	default:
+		if cursor != "" {
+			id, err := strconv.ParseUint(cursor, 10, 64)
+			if err != nil {
+				return nil, errors.New("invalid cursor")
+			}
+			db = db.Where("id > ?", id)
+		}
+		return db.Order("id"), nil
+	}
+}
+
+// commentCursor 生成指向该评论之后的游标（私有方法）
+func commentCursor(comment database.Comment, order appTypes.CommentSort) string {
+	if order == appTypes.CommentMostLiked {
+		return strconv.Itoa(comment.Likes) + "_" + strconv.FormatUint(uint64(comment.ID), 10)
+	}
+	return strconv.FormatUint(uint64(comment.ID), 10)
+}
@@ -40,7 +40,7 @@ func (cs *CommentService) DeleteCommentAndChildren(tx *gorm.DB, commentID uint)
 		}
 	}
 
-	if err := tx.Delete(&database.Comment{}, commentID).Error; err != nil {
+	if err := tx.Delete(&database.Comment{MODEL: global.MODEL{ID: commentID}}).Error; err != nil {
 		return err
 	}
 	return nil
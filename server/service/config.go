@@ -35,10 +35,15 @@ func (configService *ConfigService) UpdateWebsite(website config.Website) error
 		if err := utils.ChangeImagesCategory(global.DB, added, appTypes.System); err != nil {
 			return err
 		}
+		// 网站地址变化后站点地图中的链接需要重新生成
+		siteURLChanged := global.Config.Website.SiteURL != website.SiteURL
 		global.Config.Website = website
 		if err := utils.SaveYAML(); err != nil {
 			return err
 		}
+		if siteURLChanged {
+			return ServiceGroupApp.SitemapService.ClearSitemap()
+		}
 		return nil
 	})
 }
@@ -73,4 +78,19 @@ func (configService *ConfigService) UpdateJwt(jwt config.Jwt) error {
 func (configService *ConfigService) UpdateGaode(gaode config.Gaode) error {
 	global.Config.Gaode = gaode
 	return utils.SaveYAML()
-}
\ No newline at end of file
+}
+
+func (configService *ConfigService) UpdateComment(comment config.Comment) error {
+	global.Config.Comment = comment
+	return utils.SaveYAML()
+}
+
+func (configService *ConfigService) UpdateFilter(filter config.Filter) error {
+	global.Config.Filter = filter
+	return utils.SaveYAML()
+}
+
+func (configService *ConfigService) UpdateNewsletter(newsletter config.Newsletter) error {
+	global.Config.Newsletter = newsletter
+	return utils.SaveYAML()
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm/clause"
+	"server/global"
+	"server/model/database"
+	"server/model/other"
+	"server/model/request"
+	"server/utils"
+	"server/utils/contentFilter"
+	"strings"
+	"sync"
+	"time"
+)
+
+type ContentFilterService struct {
+}
+
+// keywordFilterTTL 敏感词自动机的缓存时间，多实例部署时其他实例修改的敏感词最迟在该时间后生效
+const keywordFilterTTL = 5 * time.Minute
+
+var keywordFilterCache struct {
+	sync.Mutex
+	filter   *contentFilter.KeywordFilter
+	loadedAt time.Time
+}
+
+// Check 使用敏感词、链接数、重复内容和发布频率规则检查内容，命中规则时记录过滤日志
+func (contentFilterService *ContentFilterService) Check(scene string, userUUID uuid.UUID, content string) (contentFilter.Result, error) {
+	keywordFilter, err := contentFilterService.keywordFilter()
+	if err != nil {
+		return contentFilter.Result{}, err
+	}
+
+	conf := global.Config.Filter
+	pipeline := contentFilter.Pipeline{keywordFilter}
+	if conf.LinkAction != "" {
+		pipeline = append(pipeline, &contentFilter.LinkFilter{Max: conf.MaxLinks, Action: conf.LinkAction})
+	}
+	if conf.DuplicateWindow > 0 {
+		pipeline = append(pipeline, &contentFilter.DuplicateFilter{Window: conf.DuplicateDuration(), Action: conf.DuplicateAction})
+	}
+	if conf.RateLimit > 0 && conf.RateWindow > 0 {
+		pipeline = append(pipeline, &contentFilter.RateFilter{Limit: conf.RateLimit, Window: conf.RateDuration(), Action: conf.RateAction})
+	}
+
+	result, err := pipeline.Run(contentFilter.Input{Scene: scene, UserUUID: userUUID.String(), Content: content})
+	if err != nil {
+		return contentFilter.Result{}, err
+	}
+	if len(result.Hits) > 0 {
+		if err := global.DB.Create(&database.FilterLog{
+			Scene:    scene,
+			UserUUID: userUUID,
+			Content:  content,
+			Result:   result.Content,
+			Action:   result.Action,
+			Hits:     result.Hits,
+		}).Error; err != nil {
+			return contentFilter.Result{}, err
+		}
+	}
+	return result, nil
+}
+
+// SensitiveWordList 敏感词列表
+func (contentFilterService *ContentFilterService) SensitiveWordList(info request.SensitiveWordList) (interface{}, int64, error) {
+	db := global.DB.Model(&database.SensitiveWord{})
+
+	if info.Word != nil {
+		db = db.Where("word LIKE ?", "%"+*info.Word+"%")
+	}
+
+	if info.Action != nil {
+		db = db.Where("action = ?", *info.Action)
+	}
+
+	option := other.MySQLOption{
+		PageInfo: info.PageInfo,
+		Where:    db,
+	}
+
+	return utils.MySQLPagination(&database.SensitiveWord{}, option)
+}
+
+// SensitiveWordCreate 批量添加敏感词，已存在的敏感词会更新处理方式
+func (contentFilterService *ContentFilterService) SensitiveWordCreate(req request.SensitiveWordCreate) error {
+	words := make([]database.SensitiveWord, 0, len(req.Words))
+	seen := make(map[string]bool)
+	for _, word := range req.Words {
+		word = strings.TrimSpace(word)
+		if word == "" || seen[strings.ToLower(word)] {
+			continue
+		}
+		seen[strings.ToLower(word)] = true
+		words = append(words, database.SensitiveWord{Word: word, Action: req.Action})
+	}
+	if len(words) == 0 {
+		return nil
+	}
+	if err := global.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "word"}},
+		DoUpdates: clause.AssignmentColumns([]string{"action", "updated_at"}),
+	}).Create(&words).Error; err != nil {
+		return err
+	}
+	contentFilterService.clearKeywordFilter()
+	return nil
+}
+
+// SensitiveWordDelete 删除敏感词
+func (contentFilterService *ContentFilterService) SensitiveWordDelete(req request.SensitiveWordDelete) error {
+	if len(req.IDs) == 0 {
+		return nil
+	}
+	if err := global.DB.Unscoped().Delete(&database.SensitiveWord{}, req.IDs).Error; err != nil {
+		return err
+	}
+	contentFilterService.clearKeywordFilter()
+	return nil
+}
+
+// FilterLogList 内容过滤记录列表
+func (contentFilterService *ContentFilterService) FilterLogList(info request.FilterLogList) (interface{}, int64, error) {
+	db := global.DB.Model(&database.FilterLog{})
+
+	if info.Scene != nil {
+		db = db.Where("scene = ?", *info.Scene)
+	}
+
+	if info.Action != nil {
+		db = db.Where("action = ?", *info.Action)
+	}
+
+	option := other.MySQLOption{
+		PageInfo: info.PageInfo,
+		Where:    db,
+	}
+
+	return utils.MySQLPagination(&database.FilterLog{}, option)
+}
+
+// keywordFilter 获取缓存的敏感词过滤规则，缓存过期后重新从数据库构建
+func (contentFilterService *ContentFilterService) keywordFilter() (*contentFilter.KeywordFilter, error) {
+	keywordFilterCache.Lock()
+	defer keywordFilterCache.Unlock()
+	if keywordFilterCache.filter != nil && time.Since(keywordFilterCache.loadedAt) < keywordFilterTTL {
+		return keywordFilterCache.filter, nil
+	}
+
+	var words []database.SensitiveWord
+	if err := global.DB.Select("word", "action").Find(&words).Error; err != nil {
+		return nil, err
+	}
+	keywords := make([]contentFilter.Keyword, 0, len(words))
+	for _, word := range words {
+		keywords = append(keywords, contentFilter.Keyword{Word: word.Word, Action: word.Action})
+	}
+	keywordFilterCache.filter = contentFilter.NewKeywordFilter(keywords)
+	keywordFilterCache.loadedAt = time.Now()
+	return keywordFilterCache.filter, nil
+}
+
+// clearKeywordFilter 敏感词变化后清除缓存
+func (contentFilterService *ContentFilterService) clearKeywordFilter() {
+	keywordFilterCache.Lock()
+	keywordFilterCache.filter = nil
+	keywordFilterCache.Unlock()
+}
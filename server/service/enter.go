@@ -17,6 +17,16 @@ type ServiceGroup struct {
 	HotSearchService
 	CalendarService
 	ConfigService
+	FeedService
+	SitemapService
+	OutboxService
+	ReconcileService
+	TrafficService
+	ContentFilterService
+	NotificationService
+	StreamService
+	MailService
+	NewsletterService
 }
 
 var ServiceGroupApp = new(ServiceGroup)
\ No newline at end of file
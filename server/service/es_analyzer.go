@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"go.uber.org/zap"
+	"server/global"
+	"server/model/elasticsearch"
+)
+
+// AnalyzerAvailable 检查 ES 中是否存在指定的分词器，例如是否安装了 IK 分词插件
+func (esService *EsService) AnalyzerAvailable(name string) bool {
+	_, err := global.ESClient.Indices.Analyze().Analyzer(name).Text("Go 博客").Do(context.TODO())
+	return err == nil
+}
+
+// CheckAnalyzers 检查配置的分词器是否可用，不可用时回退为内置的 cjk 分词器
+func (esService *EsService) CheckAnalyzers() {
+	checked := map[string]bool{}
+	for _, a := range global.Config.ES.Analyzers {
+		for _, name := range []string{a.Index, a.Search} {
+			if name == "" || checked[name] {
+				continue
+			}
+			checked[name] = true
+			if !esService.AnalyzerAvailable(name) {
+				global.Log.Warn("Analyzer is not available, falling back to "+elasticsearch.FallbackAnalyzer, zap.String("analyzer", name))
+				elasticsearch.SetAnalyzerUnavailable(name)
+			}
+		}
+	}
+}
+
+// ArticleAnalyzersChanged 比较文章索引中文本字段的分词器与当前配置是否一致
+func (esService *EsService) ArticleAnalyzersChanged() (bool, error) {
+	mapping, err := esService.IndexMapping(elasticsearch.ArticleIndex())
+	if err != nil {
+		return false, err
+	}
+	for _, field := range elasticsearch.ArticleTextFields {
+		var index, search string
+		if p, ok := mapping.Properties[field].(*types.TextProperty); ok {
+			if p.Analyzer != nil {
+				index = *p.Analyzer
+			}
+			search = index
+			if p.SearchAnalyzer != nil {
+				search = *p.SearchAnalyzer
+			}
+		}
+		a := elasticsearch.ArticleAnalyzer(field)
+		if index != a.Index || search != a.Search {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"go.uber.org/zap"
+	"server/global"
+	"server/model/elasticsearch"
+	"server/utils"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	articleReindexLock    = "es:article:reindex" // 重建文章索引的锁，同一时间只允许一个实例重建
+	articleReindexLockTTL = time.Hour            // 重建文章索引的最长时间，超时后锁自动释放
+)
+
+// ErrArticleReindexRunning 其他实例正在重建文章索引
+var ErrArticleReindexRunning = errors.New("the article index is being rebuilt by another instance")
+
+// ArticleReindexRunning 是否正在重建文章索引，不经过发件箱直接写入 ES 的操作需要在重建期间暂停，否则写入会丢失
+func (esService *EsService) ArticleReindexRunning() (bool, error) {
+	n, err := global.Redis.Exists(articleReindexLock).Result()
+	return n > 0, err
+}
+
+// ArticleIndexVersions 获取文章索引已有的所有版本号，按从小到大排序
+func (esService *EsService) ArticleIndexVersions() ([]int, error) {
+	prefix := elasticsearch.ArticleIndex() + "_v"
+	names, err := esService.IndexList(prefix + "*")
+	if err != nil {
+		return nil, err
+	}
+	var versions []int
+	for _, name := range names {
+		if version, err := strconv.Atoi(strings.TrimPrefix(name, prefix)); err == nil {
+			versions = append(versions, version)
+		}
+	}
+	sort.Ints(versions)
+	return versions, nil
+}
+
+// ArticleIndexCurrent 获取文章别名当前指向的索引
+// 旧版本直接以 article_index 为名创建索引，此时 legacy 为 true
+func (esService *EsService) ArticleIndexCurrent() (current string, legacy bool, err error) {
+	indices, err := esService.AliasIndices(elasticsearch.ArticleIndex())
+	if err != nil {
+		return "", false, err
+	}
+	if len(indices) > 0 {
+		return indices[0], false, nil
+	}
+	exists, err := esService.IndexExists(elasticsearch.ArticleIndex())
+	if err != nil || !exists {
+		return "", false, err
+	}
+	return elasticsearch.ArticleIndex(), true, nil
+}
+
+// ArticleIndexCreate 创建新版本的文章索引，并将文章别名指向它
+func (esService *EsService) ArticleIndexCreate() error {
+	versions, err := esService.ArticleIndexVersions()
+	if err != nil {
+		return err
+	}
+	index := elasticsearch.ArticleIndexVersion(nextVersion(versions))
+	if err := esService.IndexCreate(index, elasticsearch.ArticleMapping()); err != nil {
+		return err
+	}
+	alias := elasticsearch.ArticleIndex()
+	return esService.AliasUpdate(types.IndicesAction{Add: &types.AddAction{Index: &index, Alias: &alias}})
+}
+
+// ArticleIndexDelete 删除文章索引的所有版本
+func (esService *EsService) ArticleIndexDelete() error {
+	versions, err := esService.ArticleIndexVersions()
+	if err != nil {
+		return err
+	}
+	for _, version := range versions {
+		if err := esService.IndexDelete(elasticsearch.ArticleIndexVersion(version)); err != nil {
+			return err
+		}
+	}
+	// 旧版本以 article_index 为名创建的索引
+	_, legacy, err := esService.ArticleIndexCurrent()
+	if err != nil {
+		return err
+	}
+	if legacy {
+		return esService.IndexDelete(elasticsearch.ArticleIndex())
+	}
+	return nil
+}
+
+// ArticleReindex 使用当前的映射创建新版本的文章索引并复制所有文档，校验文档数后原子地切换别名
+// 切换后保留上一个版本用于回滚，更早的版本会被删除，返回新索引的名称及复制的文档数
+func (esService *EsService) ArticleReindex() (string, int64, error) {
+	unlock, ok, err := utils.Lock(articleReindexLock, articleReindexLockTTL)
+	if err != nil {
+		return "", 0, err
+	}
+	if !ok {
+		return "", 0, ErrArticleReindexRunning
+	}
+	defer unlock()
+	return esService.articleReindex()
+}
+
+// ArticleReanalyze 文章索引的分词器与当前配置不一致时重建文章索引，使已有文章按新的分词器重新分词
+// 索引不存在或分词器没有变化时返回的索引名称为空
+func (esService *EsService) ArticleReanalyze() (string, int64, error) {
+	unlock, ok, err := utils.Lock(articleReindexLock, articleReindexLockTTL)
+	if err != nil {
+		return "", 0, err
+	}
+	if !ok {
+		return "", 0, ErrArticleReindexRunning
+	}
+	defer unlock()
+
+	// 获取锁之后再比较，其他实例可能已经完成了重建
+	exists, err := esService.IndexExists(elasticsearch.ArticleIndex())
+	if err != nil || !exists {
+		return "", 0, err
+	}
+	changed, err := esService.ArticleAnalyzersChanged()
+	if err != nil || !changed {
+		return "", 0, err
+	}
+	return esService.articleReindex()
+}
+
+// articleReindex 重建文章索引，调用方需要持有 articleReindexLock
+// 复制期间暂停发件箱投递，使旧索引保持不变，复制完成后把暂停期间的写入重放到新索引再切换别名
+func (esService *EsService) articleReindex() (string, int64, error) {
+	alias := elasticsearch.ArticleIndex()
+	current, legacy, err := esService.ArticleIndexCurrent()
+	if err != nil {
+		return "", 0, err
+	}
+	if current == "" {
+		return "", 0, errors.New("the article index does not exist")
+	}
+
+	versions, err := esService.ArticleIndexVersions()
+	if err != nil {
+		return "", 0, err
+	}
+
+	resume, err := ServiceGroupApp.OutboxService.Pause(articleReindexLockTTL)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resume()
+
+	// 旧版本的索引无法与别名同名，切换时会被删除，先按原映射复制一份作为 v0 用于回滚
+	if legacy {
+		previous := elasticsearch.ArticleIndexVersion(0)
+		if err := esService.copyIndex(current, previous, nil); err != nil {
+			return "", 0, err
+		}
+		versions = append(versions, 0)
+	}
+
+	index := elasticsearch.ArticleIndexVersion(nextVersion(versions))
+	if err := esService.copyIndex(current, index, elasticsearch.ArticleMapping()); err != nil {
+		return "", 0, err
+	}
+
+	// 复制期间写入发件箱的事件先投递到新索引，再切换别名
+	if err := ServiceGroupApp.OutboxService.Replay(map[string]string{alias: index}); err != nil {
+		_ = esService.IndexDelete(index)
+		return "", 0, err
+	}
+	actions := []types.IndicesAction{{Add: &types.AddAction{Index: &index, Alias: &alias}}}
+	if legacy {
+		actions = append(actions, types.IndicesAction{RemoveIndex: &types.RemoveIndexAction{Index: &current}})
+	} else {
+		actions = append(actions, types.IndicesAction{Remove: &types.RemoveAction{Index: &current, Alias: &alias}})
+	}
+	if err := esService.AliasUpdate(actions...); err != nil {
+		return "", 0, err
+	}
+
+	total, err := esService.IndexCount(index)
+	if err != nil {
+		return "", 0, err
+	}
+
+	// 只保留上一个版本
+	previous := current
+	if legacy {
+		previous = elasticsearch.ArticleIndexVersion(0)
+	}
+	for _, version := range versions {
+		if name := elasticsearch.ArticleIndexVersion(version); name != previous {
+			if err := esService.IndexDelete(name); err != nil {
+				global.Log.Error("Failed to delete old article index:", zap.String("index", name), zap.Error(err))
+			}
+		}
+	}
+	return index, total, nil
+}
+
+// ArticleIndexRollback 将文章别名切换回上一个版本的索引，返回切换后的索引名称
+func (esService *EsService) ArticleIndexRollback() (string, error) {
+	alias := elasticsearch.ArticleIndex()
+	current, legacy, err := esService.ArticleIndexCurrent()
+	if err != nil {
+		return "", err
+	}
+	if current == "" || legacy {
+		return "", errors.New("the article index has no previous version")
+	}
+
+	versions, err := esService.ArticleIndexVersions()
+	if err != nil {
+		return "", err
+	}
+	previous := ""
+	for _, version := range versions {
+		name := elasticsearch.ArticleIndexVersion(version)
+		if name == current {
+			break
+		}
+		previous = name
+	}
+	if previous == "" {
+		return "", errors.New("the article index has no previous version")
+	}
+
+	return previous, esService.AliasUpdate(
+		types.IndicesAction{Add: &types.AddAction{Index: &previous, Alias: &alias}},
+		types.IndicesAction{Remove: &types.RemoveAction{Index: &current, Alias: &alias}},
+	)
+}
+
+// copyIndex 创建目标索引并复制源索引中的所有文档，复制后校验两者文档数一致
+// mapping 为空时使用源索引的映射
+func (esService *EsService) copyIndex(source, dest string, mapping *types.TypeMapping) error {
+	if mapping == nil {
+		var err error
+		if mapping, err = esService.IndexMapping(source); err != nil {
+			return err
+		}
+	}
+	if err := esService.IndexCreate(dest, mapping); err != nil {
+		return err
+	}
+	if _, err := esService.IndexReindex(source, dest); err != nil {
+		return err
+	}
+
+	sourceCount, err := esService.IndexCount(source)
+	if err != nil {
+		return err
+	}
+	destCount, err := esService.IndexCount(dest)
+	if err != nil {
+		return err
+	}
+	if destCount < sourceCount {
+		// 复制不完整时删除目标索引，不影响当前使用的索引
+		_ = esService.IndexDelete(dest)
+		return fmt.Errorf("document count mismatch after copying %s to %s: %d != %d", source, dest, destCount, sourceCount)
+	}
+	return nil
+}
+
+// nextVersion 获取下一个索引版本号
+func nextVersion(versions []int) int {
+	if len(versions) == 0 {
+		return 1
+	}
+	return versions[len(versions)-1] + 1
+}
@@ -2,8 +2,12 @@ package service
 
 import (
 	"context"
+	"errors"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/conflicts"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/versiontype"
 	"server/global"
+	"sort"
 )
 
 // EsService 提供了对 Elasticsearch 索引的操作方法
@@ -24,4 +28,107 @@ func (esService *EsService) IndexDelete(indexName string) error {
 // IndexExists 检查指定的 Elasticsearch 索引是否存在
 func (esService *EsService) IndexExists(indexName string) (bool, error) {
 	return global.ESClient.Indices.Exists(indexName).Do(context.TODO())
-}
\ No newline at end of file
+}
+
+// IndexPutMapping 向已存在的索引中添加字段映射，只能新增字段或子字段，不能修改已有字段的类型
+func (esService *EsService) IndexPutMapping(indexName string, properties map[string]types.Property) error {
+	_, err := global.ESClient.Indices.PutMapping(indexName).Properties(properties).Do(context.TODO())
+	return err
+}
+
+// IndexUpdateDocs 重新索引所有文档，使新增的字段映射对已有数据生效，返回更新的文档数
+func (esService *EsService) IndexUpdateDocs(indexName string) (int64, error) {
+	res, err := global.ESClient.UpdateByQuery(indexName).
+		Conflicts(conflicts.Proceed).
+		Refresh(true).
+		WaitForCompletion(true).
+		Do(context.TODO())
+	if err != nil {
+		return 0, err
+	}
+	if res.Updated == nil {
+		return 0, nil
+	}
+	return *res.Updated, nil
+}
+
+// IndexMapping 获取索引当前的映射
+func (esService *EsService) IndexMapping(indexName string) (*types.TypeMapping, error) {
+	res, err := global.ESClient.Indices.GetMapping().Index(indexName).Do(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range res {
+		return &record.Mappings, nil
+	}
+	return nil, errors.New("index mapping not found")
+}
+
+// IndexReindex 将源索引中的文档复制到目标索引，返回复制的文档数
+// 使用外部版本号复制，目标索引中版本更新的文档不会被覆盖，因此可以重复执行以补齐复制期间的写入
+func (esService *EsService) IndexReindex(source, dest string) (int64, error) {
+	res, err := global.ESClient.Reindex().
+		Source(&types.ReindexSource{Index: []string{source}}).
+		Dest(&types.ReindexDestination{Index: dest, VersionType: &versiontype.External}).
+		Conflicts(conflicts.Proceed).
+		Refresh(true).
+		WaitForCompletion(true).
+		Do(context.TODO())
+	if err != nil {
+		return 0, err
+	}
+	if len(res.Failures) > 0 {
+		return 0, errors.New("reindex failed: " + res.Failures[0].Cause.Type)
+	}
+	if res.Total == nil {
+		return 0, nil
+	}
+	return *res.Total, nil
+}
+
+// IndexCount 获取索引中的文档数
+func (esService *EsService) IndexCount(indexName string) (int64, error) {
+	res, err := global.ESClient.Count().Index(indexName).Do(context.TODO())
+	if err != nil {
+		return 0, err
+	}
+	return res.Count, nil
+}
+
+// IndexList 获取名称匹配指定通配符的所有索引，按名称排序
+func (esService *EsService) IndexList(pattern string) ([]string, error) {
+	res, err := global.ESClient.Indices.Get(pattern).AllowNoIndices(true).IgnoreUnavailable(true).Do(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for name := range res {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// AliasIndices 获取别名指向的索引，别名不存在时返回空
+func (esService *EsService) AliasIndices(alias string) ([]string, error) {
+	exists, err := global.ESClient.Indices.ExistsAlias(alias).Do(context.TODO())
+	if err != nil || !exists {
+		return nil, err
+	}
+	res, err := global.ESClient.Indices.GetAlias().Name(alias).Do(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for name := range res {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// AliasUpdate 原子地执行一组别名操作
+func (esService *EsService) AliasUpdate(actions ...types.IndicesAction) error {
+	_, err := global.ESClient.Indices.UpdateAliases().Actions(actions...).Do(context.TODO())
+	return err
+}
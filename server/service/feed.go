@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/sortorder"
+	"server/global"
+	"server/model/elasticsearch"
+	"server/model/other"
+	"server/model/request"
+	"server/utils"
+	"time"
+)
+
+// feedSize 订阅源中包含的文章数量
+const feedSize = 20
+
+type FeedService struct {
+}
+
+// feedArticle 订阅源中的一篇文章
+type feedArticle struct {
+	ID        string
+	Article   elasticsearch.Article
+	Published time.Time
+	Updated   time.Time
+}
+
+// Feed 生成文章订阅源，返回订阅源内容、Content-Type 以及最后修改时间
+// siteURL 为网站地址，selfURL 为订阅源自身的地址
+func (feedService *FeedService) Feed(req request.Feed, siteURL, selfURL string) ([]byte, string, time.Time, error) {
+	articles, err := feedService.feedArticles(req)
+	if err != nil {
+		return nil, "", time.Time{}, err
+	}
+
+	// 订阅源的最后修改时间取文章中最新的更新时间
+	var lastModified time.Time
+	for _, a := range articles {
+		if a.Updated.After(lastModified) {
+			lastModified = a.Updated
+		}
+	}
+
+	title := global.Config.Website.Title
+	if req.Category != "" {
+		title += " - " + req.Category
+	} else if req.Tag != "" {
+		title += " - #" + req.Tag
+	}
+
+	var body []byte
+	var contentType string
+	switch req.Format {
+	case "atom":
+		body, err = feedService.atom(articles, title, siteURL, selfURL, lastModified)
+		contentType = "application/atom+xml; charset=utf-8"
+	case "json":
+		body, err = feedService.jsonFeed(articles, title, siteURL, selfURL)
+		contentType = "application/feed+json; charset=utf-8"
+	default:
+		body, err = feedService.rss(articles, title, siteURL, selfURL, lastModified)
+		contentType = "application/rss+xml; charset=utf-8"
+	}
+	if err != nil {
+		return nil, "", time.Time{}, err
+	}
+	return body, contentType, lastModified, nil
+}
+
+// feedArticles 按发布时间倒序获取已发布的文章，可按类别或标签筛选
+func (feedService *FeedService) feedArticles(req request.Feed) ([]feedArticle, error) {
+	filter := []types.Query{publishedQuery()}
+	if req.Category != "" {
+		filter = append(filter, types.Query{Term: map[string]types.TermQuery{"category": {Value: req.Category}}})
+	}
+	if req.Tag != "" {
+		filter = append(filter, types.Query{Match: map[string]types.MatchQuery{"tags": {Query: req.Tag}}})
+	}
+
+	size := feedSize
+	searchReq := &search.Request{
+		Query: &types.Query{Bool: &types.BoolQuery{Filter: filter}},
+		Sort: []types.SortCombinations{
+			// 引入发布状态之前的文章没有发布时间，排在最后并按创建时间排序
+			types.SortOptions{SortOptions: map[string]types.FieldSort{"publish_at": {Order: &sortorder.Desc}}},
+			types.SortOptions{SortOptions: map[string]types.FieldSort{"created_at": {Order: &sortorder.Desc}}},
+		},
+		Size: &size,
+	}
+	res, err := global.ESClient.Search().
+		Index(elasticsearch.ArticleIndex()).
+		Request(searchReq).
+		SourceIncludes_("created_at", "updated_at", "publish_at", "cover", "title", "slug", "category", "tags", "abstract").
+		Do(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+
+	var articles []feedArticle
+	for _, hit := range res.Hits.Hits {
+		var a elasticsearch.Article
+		if err := json.Unmarshal(hit.Source_, &a); err != nil {
+			return nil, err
+		}
+		published := utils.ParseDateTime(a.PublishAt)
+		if published.IsZero() {
+			published = utils.ParseDateTime(a.CreatedAt)
+		}
+		updated := utils.ParseDateTime(a.UpdatedAt)
+		if updated.Before(published) {
+			updated = published
+		}
+		articles = append(articles, feedArticle{ID: *hit.Id_, Article: a, Published: published, Updated: updated})
+	}
+	return articles, nil
+}
+
+// rss 生成 RSS 2.0 订阅源
+func (feedService *FeedService) rss(articles []feedArticle, title, siteURL, selfURL string, lastModified time.Time) ([]byte, error) {
+	website := global.Config.Website
+	channel := other.RSSChannel{
+		Title:       title,
+		Link:        siteURL,
+		Description: website.Description,
+		Language:    "zh-CN",
+		AtomLink:    other.AtomLink{Href: selfURL, Rel: "self", Type: "application/rss+xml"},
+	}
+	if website.Email != "" {
+		channel.ManagingEditor = website.Email + " (" + website.Name + ")"
+	}
+	if !lastModified.IsZero() {
+		channel.LastBuildDate = lastModified.Format(time.RFC1123Z)
+	}
+	for _, a := range articles {
+		link := articlePermalink(siteURL, a.ID, a.Article.Slug)
+		channel.Items = append(channel.Items, other.RSSItem{
+			Title:       a.Article.Title,
+			Link:        link,
+			GUID:        link,
+			Description: a.Article.Abstract,
+			Category:    append([]string{a.Article.Category}, a.Article.Tags...),
+			PubDate:     a.Published.Format(time.RFC1123Z),
+		})
+	}
+
+	body, err := xml.MarshalIndent(other.RSS{Version: "2.0", Atom: "http://www.w3.org/2005/Atom", Channel: channel}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// atom 生成 Atom 订阅源
+func (feedService *FeedService) atom(articles []feedArticle, title, siteURL, selfURL string, lastModified time.Time) ([]byte, error) {
+	website := global.Config.Website
+	if lastModified.IsZero() {
+		lastModified = time.Now()
+	}
+	feed := other.AtomFeed{
+		Xmlns:    "http://www.w3.org/2005/Atom",
+		ID:       selfURL,
+		Title:    title,
+		Subtitle: website.Description,
+		Updated:  lastModified.Format(time.RFC3339),
+		Links: []other.AtomLink{
+			{Href: siteURL},
+			{Href: selfURL, Rel: "self", Type: "application/atom+xml"},
+		},
+		Author: other.AtomAuthor{Name: website.Name, Email: website.Email},
+	}
+	for _, a := range articles {
+		link := articlePermalink(siteURL, a.ID, a.Article.Slug)
+		categories := []other.AtomCategory{{Term: a.Article.Category}}
+		for _, tag := range a.Article.Tags {
+			categories = append(categories, other.AtomCategory{Term: tag})
+		}
+		feed.Entries = append(feed.Entries, other.AtomEntry{
+			ID:         link,
+			Title:      a.Article.Title,
+			Link:       other.AtomLink{Href: link, Rel: "alternate"},
+			Published:  a.Published.Format(time.RFC3339),
+			Updated:    a.Updated.Format(time.RFC3339),
+			Summary:    a.Article.Abstract,
+			Categories: categories,
+		})
+	}
+
+	body, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// jsonFeed 生成 JSON Feed 订阅源
+func (feedService *FeedService) jsonFeed(articles []feedArticle, title, siteURL, selfURL string) ([]byte, error) {
+	website := global.Config.Website
+	feed := other.JSONFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       title,
+		HomePageURL: siteURL,
+		FeedURL:     selfURL,
+		Description: website.Description,
+		Authors:     []other.JSONFeedAuthor{{Name: website.Name, URL: siteURL}},
+		Items:       []other.JSONFeedItem{},
+	}
+	for _, a := range articles {
+		link := articlePermalink(siteURL, a.ID, a.Article.Slug)
+		feed.Items = append(feed.Items, other.JSONFeedItem{
+			ID:            link,
+			URL:           link,
+			Title:         a.Article.Title,
+			ContentText:   a.Article.Abstract,
+			Image:         a.Article.Cover,
+			DatePublished: a.Published.Format(time.RFC3339),
+			DateModified:  a.Updated.Format(time.RFC3339),
+			Tags:          append([]string{a.Article.Category}, a.Article.Tags...),
+		})
+	}
+	return json.Marshal(feed)
+}
+
+// articleURL 生成文章在前台的访问地址
+func articleURL(siteURL, id string) string {
+	return siteURL + "/article/" + id
+}
@@ -2,29 +2,54 @@ package service
 
 import (
 	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
 	"server/global"
+	"server/model/appTypes"
 	"server/model/database"
 	"server/model/other"
 	"server/model/request"
 	"server/utils"
+	"server/utils/contentFilter"
 )
 
 type FeedbackService struct {
 }
 
 func (feedbackService *FeedbackService) FeedbackNew() (feedbacks []database.Feedback, err error) {
-	err = global.DB.Order("id desc").Find(&feedbacks).Error
+	err = global.DB.Where("pending = ?", false).Order("id desc").Find(&feedbacks).Error
 	if err != nil {
 		return nil, err
 	}
 	return feedbacks, nil
 }
 
+// FeedbackCreate 创建反馈，内容需要经过过滤，需要审核的反馈在审核通过前不公开展示
 func (feedbackService *FeedbackService) FeedbackCreate(req request.FeedbackCreate) error {
-	return global.DB.Create(&database.Feedback{
+	result, err := ServiceGroupApp.ContentFilterService.Check("feedback", req.UUID, req.Content)
+	if err != nil {
+		return err
+	}
+	if result.Action == appTypes.FilterReject {
+		return contentFilter.ErrRejected
+	}
+	feedback := database.Feedback{
 		UserUUID: req.UUID,
-		Content:  req.Content,
-	}).Error
+		Content:  result.Content,
+		Pending:  result.Action == appTypes.FilterModerate,
+	}
+	if err := global.DB.Create(&feedback).Error; err != nil {
+		return err
+	}
+	ServiceGroupApp.StreamService.Publish(StreamAdminChannel, appTypes.StreamFeedback, feedback)
+	return nil
+}
+
+// FeedbackApprove 审核通过反馈
+func (feedbackService *FeedbackService) FeedbackApprove(req request.FeedbackApprove) error {
+	if len(req.IDs) == 0 {
+		return nil
+	}
+	return global.DB.Model(&database.Feedback{}).Where("id IN ?", req.IDs).Update("pending", false).Error
 }
 
 func (feedbackService *FeedbackService) FeedbackInfo(uuid uuid.UUID) (feedbacks []database.Feedback, err error) {
@@ -42,8 +67,22 @@ func (feedbackService *FeedbackService) FeedbackDelete(req request.FeedbackDelet
 	return global.DB.Delete(&database.Feedback{}, req.IDs).Error
 }
 
+// FeedbackReply 回复反馈并通知反馈者
 func (feedbackService *FeedbackService) FeedbackReply(req request.FeedbackReply) error {
-	return global.DB.Take(&database.Feedback{}, req.ID).Update("reply", req.Reply).Error
+	var feedback database.Feedback
+	if err := global.DB.Take(&feedback, req.ID).Update("reply", req.Reply).Error; err != nil {
+		return err
+	}
+	if err := ServiceGroupApp.NotificationService.Notify(database.Notification{
+		UserUUID:   feedback.UserUUID,
+		ActorUUID:  req.UUID,
+		Type:       appTypes.NotificationFeedbackReply,
+		FeedbackID: &feedback.ID,
+		Content:    notificationSnippet(req.Reply),
+	}); err != nil {
+		global.Log.Error("Failed to send feedback reply notification:", zap.Error(err))
+	}
+	return nil
 }
 
 func (feedbackService *FeedbackService) FeedbackList(info request.PageInfo) (interface{}, int64, error) {
@@ -0,0 +1,490 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-redis/redis"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	htmlTemplate "html/template"
+	"server/global"
+	"server/model/appTypes"
+	"server/model/database"
+	"server/model/other"
+	"server/model/request"
+	"server/model/response"
+	"server/utils"
+	"sort"
+	"strconv"
+	"text/template"
+	"time"
+)
+
+const (
+	mailQueueKey       = "mail:queue"      // 等待发送的邮件
+	mailProcessingKey  = "mail:processing" // 正在发送的邮件，发送完成后移除
+	mailRetryKey       = "mail:retry"      // 等待重试的邮件，score 为下次重试的时间
+	mailDeadKey        = "mail:dead"       // 多次重试后仍然失败的邮件
+	mailBatchSize      = 50                // 每次发送的最大邮件数
+	mailMaxAttempts    = 5                 // 超过该重试次数后移入死信队列
+	mailDeliverLock    = "mail:deliver"    // 发送邮件的锁，同一时间只有一个实例发送
+	mailDeliverLockTTL = 5 * time.Minute
+	mailDeliverBudget  = time.Minute // 每次发送的最长时间，保证在锁过期之前结束
+)
+
+// mailSensitiveData 邮件数据中不能在后台展示的字段，例如验证码和带签名的链接
+var mailSensitiveData = []string{"Code", "ConfirmURL", "UnsubscribeURL"}
+
+// MailVerificationCode 邮箱验证码邮件模板
+const MailVerificationCode = "verification_code"
+
+// MailNotification 通知邮件模板，每种通知类型一个模板
+func MailNotification(notificationType appTypes.NotificationType) string {
+	return "notification_" + string(notificationType)
+}
+
+// mailTemplateDefault 内置的默认模板
+type mailTemplateDefault struct {
+	description string
+	variables   []string
+	database.MailTemplate
+}
+
+var mailTemplateDefaults = map[string]mailTemplateDefault{
+	MailVerificationCode: {
+		description: "邮箱验证码",
+		variables:   []string{"Email", "Code", "Minutes"},
+		MailTemplate: database.MailTemplate{
+			Subject: "您的邮箱验证码",
+			HTML: `亲爱的用户[{{.Email}}]，<br/>
+<br/>
+感谢您注册{{.Website.Name}}的个人博客！为了确保您的邮箱安全，请使用以下验证码进行验证：<br/>
+<br/>
+验证码：[<font color="blue"><u>{{.Code}}</u></font>]<br/>
+该验证码在 {{.Minutes}} 分钟内有效，请尽快使用。<br/>
+<br/>
+如果您没有请求此验证码，请忽略此邮件。
+<br/>
+如有任何疑问，请联系我们的支持团队：<br/>
+邮箱：{{.Website.Email}}<br/>
+<br/>
+祝好，<br/>
+{{.Website.Title}}<br/>
+<br/>`,
+			Text: `亲爱的用户[{{.Email}}]，
+
+感谢您注册{{.Website.Name}}的个人博客！为了确保您的邮箱安全，请使用以下验证码进行验证：
+
+验证码：{{.Code}}
+该验证码在 {{.Minutes}} 分钟内有效，请尽快使用。
+
+如果您没有请求此验证码，请忽略此邮件。
+如有任何疑问，请联系我们的支持团队：{{.Website.Email}}
+
+祝好，
+{{.Website.Title}}
+`,
+		},
+	},
+	MailNotification(appTypes.NotificationCommentReply):  notificationMailTemplate("评论回复通知", "你的评论收到了新回复"),
+	MailNotification(appTypes.NotificationMention):       notificationMailTemplate("评论提及通知", "有人在评论中提到了你"),
+	MailNotification(appTypes.NotificationFeedbackReply): notificationMailTemplate("反馈回复通知", "你的反馈收到了回复"),
+	MailNotification(appTypes.NotificationCommentLike):   notificationMailTemplate("评论点赞通知", "有人赞了你的评论"),
+	MailNewsletterConfirm: {
+		description: "确认订阅",
+		variables:   []string{"ConfirmURL"},
+		MailTemplate: database.MailTemplate{
+			Subject: "请确认订阅{{.Website.Title}}",
+			HTML:    `<p>感谢订阅{{.Website.Title}}！请在 48 小时内点击下面的链接确认订阅：</p><p><a href="{{.ConfirmURL}}">确认订阅</a></p><p>如果您没有订阅，请忽略此邮件。</p>`,
+			Text:    "感谢订阅{{.Website.Title}}！请在 48 小时内打开下面的链接确认订阅：\n\n{{.ConfirmURL}}\n\n如果您没有订阅，请忽略此邮件。\n",
+		},
+	},
+	MailNewsletterArticle: newsletterMailTemplate("新文章通知", "{{.Website.Title}} 发布了新文章：{{(index .Articles 0).title}}"),
+	MailNewsletterDigest:  newsletterMailTemplate("每周文章摘要", "{{.Website.Title}} 本周文章"),
+}
+
+// notificationMailTemplate 生成通知邮件的默认模板
+func notificationMailTemplate(description, subject string) mailTemplateDefault {
+	return mailTemplateDefault{
+		description: description,
+		variables:   []string{"Content"},
+		MailTemplate: database.MailTemplate{
+			Subject: subject,
+			HTML:    "<p>" + subject + "：</p><blockquote>{{.Content}}</blockquote><p>—— {{.Website.Title}}</p>",
+			Text:    subject + "：\n\n{{.Content}}\n\n—— {{.Website.Title}}\n",
+		},
+	}
+}
+
+// newsletterMailTemplate 生成订阅邮件的默认模板，文章字段与 other.NewsletterArticle 的 json 名称一致
+func newsletterMailTemplate(description, subject string) mailTemplateDefault {
+	return mailTemplateDefault{
+		description: description,
+		variables:   []string{"Articles", "UnsubscribeURL"},
+		MailTemplate: database.MailTemplate{
+			Subject: subject,
+			HTML: `{{range .Articles}}<h3><a href="{{.url}}">{{.title}}</a></h3><p>{{.abstract}}</p>{{end}}` +
+				`<hr/><p>—— {{.Website.Title}}</p><p><a href="{{.UnsubscribeURL}}">退订</a></p>`,
+			Text: "{{range .Articles}}{{.title}}\n{{.abstract}}\n{{.url}}\n\n{{end}}—— {{.Website.Title}}\n退订：{{.UnsubscribeURL}}\n",
+		},
+	}
+}
+
+type MailService struct {
+}
+
+// Send 将邮件放入发送队列，由定时任务异步发送
+func (mailService *MailService) Send(to, name string, data map[string]any) error {
+	if _, ok := mailTemplateDefaults[name]; !ok {
+		return fmt.Errorf("unknown mail template %q", name)
+	}
+	payload, err := json.Marshal(other.MailMessage{
+		ID:       uuid.Must(uuid.NewV4()).String(),
+		To:       to,
+		Template: name,
+		Data:     data,
+	})
+	if err != nil {
+		return err
+	}
+	return global.Redis.LPush(mailQueueKey, payload).Err()
+}
+
+// Deliver 发送队列中的邮件，先把到期的重试邮件放回队列
+// 发送中的邮件保存在处理中的列表里，进程在发送过程中退出时，下一次发送会把这些邮件放回队列
+func (mailService *MailService) Deliver() error {
+	unlock, ok, err := utils.Lock(mailDeliverLock, mailDeliverLockTTL)
+	if err != nil || !ok {
+		return err
+	}
+	defer unlock()
+
+	// 持有锁时没有其他实例在发送，处理中的邮件都是上次异常退出时遗留的
+	for {
+		if err := global.Redis.RPopLPush(mailProcessingKey, mailQueueKey).Err(); err != nil {
+			if errors.Is(err, redis.Nil) {
+				break
+			}
+			return err
+		}
+	}
+
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	due, err := global.Redis.ZRangeByScore(mailRetryKey, redis.ZRangeBy{Min: "-inf", Max: now}).Result()
+	if err != nil {
+		return err
+	}
+	for _, payload := range due {
+		pipe := global.Redis.TxPipeline()
+		pipe.ZRem(mailRetryKey, payload)
+		pipe.LPush(mailQueueKey, payload)
+		if _, err := pipe.Exec(); err != nil {
+			return err
+		}
+	}
+
+	deadline := time.Now().Add(mailDeliverBudget)
+	for i := 0; i < mailBatchSize && time.Now().Before(deadline); i++ {
+		payload, err := global.Redis.RPopLPush(mailQueueKey, mailProcessingKey).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				return nil
+			}
+			return err
+		}
+
+		var message other.MailMessage
+		if err := json.Unmarshal([]byte(payload), &message); err != nil {
+			global.Log.Error("Failed to decode mail message:", zap.Error(err))
+		} else if err := mailService.deliver(message); err != nil {
+			// 留在处理中的列表里，下一次发送时重新放回队列
+			return err
+		}
+		if err := global.Redis.LRem(mailProcessingKey, 1, payload).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deliver 发送一封邮件并记录投递结果，失败的邮件按指数退避重试
+func (mailService *MailService) deliver(message other.MailMessage) error {
+	message.Attempts++
+	subject, html, text, err := mailService.render(message.Template, message.Data)
+	if err == nil {
+		err = utils.EmailWithText(message.To, subject, html, text, mailHeaders(message.Data))
+	}
+
+	log := database.MailLog{
+		MessageID: message.ID,
+		Recipient: message.To,
+		Template:  message.Template,
+		Subject:   subject,
+		Status:    appTypes.MailSent,
+		Attempt:   message.Attempts,
+	}
+	if err != nil {
+		global.Log.Warn("Failed to send mail",
+			zap.String("id", message.ID),
+			zap.String("template", message.Template),
+			zap.Int("attempts", message.Attempts),
+			zap.Error(err))
+		message.Error = err.Error()
+		log.Error = err.Error()
+		log.Status = appTypes.MailFailed
+		if message.Attempts >= mailMaxAttempts {
+			log.Status = appTypes.MailDead
+		}
+
+		payload, err := json.Marshal(message)
+		if err != nil {
+			return err
+		}
+		if log.Status == appTypes.MailDead {
+			err = global.Redis.LPush(mailDeadKey, payload).Err()
+		} else {
+			next := time.Now().Add(time.Minute << (message.Attempts - 1))
+			err = global.Redis.ZAdd(mailRetryKey, redis.Z{Score: float64(next.Unix()), Member: payload}).Err()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return global.DB.Create(&log).Error
+}
+
+// mailHeaders 带有退订链接的邮件声明 List-Unsubscribe，邮件客户端可以通过 POST 退订链接一键退订
+func mailHeaders(data map[string]any) map[string]string {
+	unsubscribeURL, ok := data["UnsubscribeURL"].(string)
+	if !ok || unsubscribeURL == "" {
+		return nil
+	}
+	return map[string]string{
+		"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+	}
+}
+
+// render 渲染邮件模板，模板中可以通过 .Website 访问网站信息
+func (mailService *MailService) render(name string, data map[string]any) (subject, html, text string, err error) {
+	tmpl, err := mailService.loadTemplate(name)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	vars := map[string]any{"Website": global.Config.Website}
+	for k, v := range data {
+		vars[k] = v
+	}
+
+	var buf bytes.Buffer
+	subjectTmpl, err := template.New("subject").Parse(tmpl.Subject)
+	if err != nil {
+		return "", "", "", err
+	}
+	if err := subjectTmpl.Execute(&buf, vars); err != nil {
+		return "", "", "", err
+	}
+	subject = buf.String()
+
+	buf.Reset()
+	htmlTmpl, err := htmlTemplate.New("html").Parse(tmpl.HTML)
+	if err != nil {
+		return "", "", "", err
+	}
+	if err := htmlTmpl.Execute(&buf, vars); err != nil {
+		return "", "", "", err
+	}
+	html = buf.String()
+
+	if tmpl.Text != "" {
+		buf.Reset()
+		textTmpl, err := template.New("text").Parse(tmpl.Text)
+		if err != nil {
+			return "", "", "", err
+		}
+		if err := textTmpl.Execute(&buf, vars); err != nil {
+			return "", "", "", err
+		}
+		text = buf.String()
+	}
+	return subject, html, text, nil
+}
+
+// loadTemplate 获取邮件模板，管理员没有修改过时使用内置的默认模板
+func (mailService *MailService) loadTemplate(name string) (database.MailTemplate, error) {
+	def, ok := mailTemplateDefaults[name]
+	if !ok {
+		return database.MailTemplate{}, fmt.Errorf("unknown mail template %q", name)
+	}
+	var tmpl database.MailTemplate
+	err := global.DB.Where("name = ?", name).Take(&tmpl).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		tmpl = def.MailTemplate
+		tmpl.Name = name
+		return tmpl, nil
+	}
+	return tmpl, err
+}
+
+// MailTemplateList 邮件模板列表
+func (mailService *MailService) MailTemplateList() ([]response.MailTemplate, error) {
+	var customized []database.MailTemplate
+	if err := global.DB.Find(&customized).Error; err != nil {
+		return nil, err
+	}
+	customizedMap := make(map[string]database.MailTemplate, len(customized))
+	for _, tmpl := range customized {
+		customizedMap[tmpl.Name] = tmpl
+	}
+
+	list := make([]response.MailTemplate, 0, len(mailTemplateDefaults))
+	for name, def := range mailTemplateDefaults {
+		item := response.MailTemplate{
+			MailTemplate: def.MailTemplate,
+			Description:  def.description,
+			Variables:    def.variables,
+		}
+		item.Name = name
+		if tmpl, ok := customizedMap[name]; ok {
+			item.MailTemplate = tmpl
+			item.Customized = true
+		}
+		list = append(list, item)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list, nil
+}
+
+// MailTemplateUpdate 修改邮件模板，保存前检查模板语法
+func (mailService *MailService) MailTemplateUpdate(req request.MailTemplateUpdate) error {
+	if _, ok := mailTemplateDefaults[req.Name]; !ok {
+		return fmt.Errorf("unknown mail template %q", req.Name)
+	}
+	if _, err := template.New("subject").Parse(req.Subject); err != nil {
+		return err
+	}
+	if _, err := htmlTemplate.New("html").Parse(req.HTML); err != nil {
+		return err
+	}
+	if _, err := template.New("text").Parse(req.Text); err != nil {
+		return err
+	}
+
+	return global.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"subject", "html", "text", "updated_at"}),
+	}).Create(&database.MailTemplate{
+		Name:    req.Name,
+		Subject: req.Subject,
+		HTML:    req.HTML,
+		Text:    req.Text,
+	}).Error
+}
+
+// MailTemplateReset 恢复默认邮件模板
+func (mailService *MailService) MailTemplateReset(req request.MailTemplateReset) error {
+	return global.DB.Where("name = ?", req.Name).Delete(&database.MailTemplate{}).Error
+}
+
+// MailLogList 邮件投递记录列表
+func (mailService *MailService) MailLogList(info request.MailLogList) (interface{}, int64, error) {
+	db := global.DB.Model(&database.MailLog{})
+
+	if info.Recipient != nil {
+		db = db.Where("recipient LIKE ?", "%"+*info.Recipient+"%")
+	}
+
+	if info.Template != nil {
+		db = db.Where("template = ?", *info.Template)
+	}
+
+	if info.Status != nil {
+		db = db.Where("status = ?", *info.Status)
+	}
+
+	option := other.MySQLOption{
+		PageInfo: info.PageInfo,
+		Where:    db,
+	}
+
+	return utils.MySQLPagination(&database.MailLog{}, option)
+}
+
+// MailDeadList 死信队列中的邮件
+func (mailService *MailService) MailDeadList() ([]other.MailMessage, error) {
+	payloads, err := global.Redis.LRange(mailDeadKey, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]other.MailMessage, 0, len(payloads))
+	for _, payload := range payloads {
+		var message other.MailMessage
+		if err := json.Unmarshal([]byte(payload), &message); err != nil {
+			return nil, err
+		}
+		messages = append(messages, redactMail(message))
+	}
+	return messages, nil
+}
+
+// redactMail 隐藏邮件数据中的敏感字段
+func redactMail(message other.MailMessage) other.MailMessage {
+	data := make(map[string]any, len(message.Data))
+	for k, v := range message.Data {
+		data[k] = v
+	}
+	for _, key := range mailSensitiveData {
+		if _, ok := data[key]; ok {
+			data[key] = "******"
+		}
+	}
+	message.Data = data
+	return message
+}
+
+// MailDeadRetry 将死信队列中的邮件重新放入发送队列，重试次数清零
+func (mailService *MailService) MailDeadRetry(req request.MailDeadRetry) error {
+	payloads, err := global.Redis.LRange(mailDeadKey, 0, -1).Result()
+	if err != nil {
+		return err
+	}
+	ids := make(map[string]bool, len(req.IDs))
+	for _, id := range req.IDs {
+		ids[id] = true
+	}
+
+	for _, payload := range payloads {
+		var message other.MailMessage
+		if err := json.Unmarshal([]byte(payload), &message); err != nil {
+			return err
+		}
+		if len(ids) > 0 && !ids[message.ID] {
+			continue
+		}
+
+		removed, err := global.Redis.LRem(mailDeadKey, 1, payload).Result()
+		if err != nil {
+			return err
+		}
+		if removed == 0 {
+			continue
+		}
+		message.Attempts = 0
+		message.Error = ""
+		retry, err := json.Marshal(message)
+		if err != nil {
+			return err
+		}
+		if err := global.Redis.LPush(mailQueueKey, retry).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/sortorder"
+	"github.com/go-redis/redis"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"net/url"
+	"server/global"
+	"server/model/database"
+	"server/model/elasticsearch"
+	"server/model/other"
+	"server/model/request"
+	"server/utils"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	newsletterPendingPrefix = "newsletter:pending:" // 等待确认的订阅
+	newsletterPendingTTL    = 48 * time.Hour        // 确认链接的有效期
+	newsletterDigestSize    = 20                    // 每周摘要最多包含的文章数
+	newsletterWindow        = time.Hour             // 订阅次数的统计窗口
+	newsletterEmailLimit    = 3                     // 每个邮箱在统计窗口内最多订阅的次数
+	newsletterIPLimit       = 10                    // 每个 IP 在统计窗口内最多订阅的次数
+)
+
+// 订阅相关的邮件模板
+const (
+	MailNewsletterConfirm = "newsletter_confirm"
+	MailNewsletterArticle = "newsletter_article"
+	MailNewsletterDigest  = "newsletter_digest"
+)
+
+var (
+	ErrNewsletterLinkInvalid = errors.New("invalid newsletter link")
+	ErrNewsletterLinkExpired = errors.New("the subscription confirmation link has expired")
+	ErrNewsletterTooFrequent = errors.New("subscriptions are requested too frequently, please try again later")
+	// ErrNewsletterSiteURLRequired 未配置网站地址时无法生成邮件中的链接，不发送订阅邮件
+	ErrNewsletterSiteURLRequired = errors.New("website.site_url is required to send newsletters")
+)
+
+type NewsletterService struct {
+}
+
+// Subscribe 保存待确认的订阅并发送确认邮件，确认后才会写入订阅者表
+// 同一邮箱和同一 IP 的订阅次数都有限制，避免被用来向他人邮箱批量发送确认邮件
+func (newsletterService *NewsletterService) Subscribe(req request.NewsletterSubscribe, ip string) error {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	confirmURL, err := newsletterLink("confirm", email)
+	if err != nil {
+		return err
+	}
+
+	for key, limit := range map[string]int64{
+		"newsletter:limit:email:" + email: newsletterEmailLimit,
+		"newsletter:limit:ip:" + ip:       newsletterIPLimit,
+	} {
+		count, err := utils.IncrWindow(key, newsletterWindow)
+		if err != nil {
+			return err
+		}
+		if count > limit {
+			return ErrNewsletterTooFrequent
+		}
+	}
+
+	pending, err := json.Marshal(request.NewsletterSubscribe{
+		Email:      email,
+		Categories: req.Categories,
+		Tags:       req.Tags,
+	})
+	if err != nil {
+		return err
+	}
+	if err := global.Redis.Set(newsletterPendingPrefix+email, pending, newsletterPendingTTL).Err(); err != nil {
+		return err
+	}
+	return ServiceGroupApp.MailService.Send(email, MailNewsletterConfirm, map[string]any{
+		"ConfirmURL": confirmURL,
+	})
+}
+
+// Confirm 通过确认链接完成订阅，重复订阅时更新订阅的类别和标签
+func (newsletterService *NewsletterService) Confirm(req request.NewsletterLink) error {
+	email := strings.ToLower(req.Email)
+	if !utils.HMACVerify(global.Config.Newsletter.Secret, "confirm|"+email, req.Signature) {
+		return ErrNewsletterLinkInvalid
+	}
+
+	pending, err := global.Redis.Get(newsletterPendingPrefix + email).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return ErrNewsletterLinkExpired
+		}
+		return err
+	}
+	var subscribe request.NewsletterSubscribe
+	if err := json.Unmarshal([]byte(pending), &subscribe); err != nil {
+		return err
+	}
+
+	if err := global.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "email"}},
+		DoUpdates: clause.AssignmentColumns([]string{"categories", "tags", "updated_at"}),
+	}).Create(&database.Subscriber{
+		Email:      email,
+		Categories: subscribe.Categories,
+		Tags:       subscribe.Tags,
+	}).Error; err != nil {
+		return err
+	}
+	return global.Redis.Del(newsletterPendingPrefix + email).Err()
+}
+
+// Unsubscribe 通过退订链接取消订阅
+func (newsletterService *NewsletterService) Unsubscribe(req request.NewsletterLink) error {
+	email := strings.ToLower(req.Email)
+	if !utils.HMACVerify(global.Config.Newsletter.Secret, "unsubscribe|"+email, req.Signature) {
+		return ErrNewsletterLinkInvalid
+	}
+	return global.DB.Unscoped().Where("email = ?", email).Delete(&database.Subscriber{}).Error
+}
+
+// Announce 在事务中创建新文章通知，未开启发布通知时忽略
+func (newsletterService *NewsletterService) Announce(tx *gorm.DB, id string, article elasticsearch.Article) error {
+	if !global.Config.Newsletter.OnPublish {
+		return nil
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&database.NewsletterIssue{
+		Template:  MailNewsletterArticle,
+		ArticleID: &id,
+		Articles:  []other.NewsletterArticle{newsletterArticle(id, article)},
+	}).Error
+}
+
+// Digest 创建每周摘要，汇总最近一周发布的文章，未开启每周摘要或没有新文章时忽略
+func (newsletterService *NewsletterService) Digest() error {
+	if !global.Config.Newsletter.WeeklyDigest {
+		return nil
+	}
+
+	weekAgo := time.Now().AddDate(0, 0, -7).Format("2006-01-02 15:04:05")
+	size := newsletterDigestSize
+	searchReq := &search.Request{
+		Query: &types.Query{Bool: &types.BoolQuery{Filter: []types.Query{
+			publishedQuery(),
+			{Range: map[string]types.RangeQuery{"publish_at": types.DateRangeQuery{Gte: &weekAgo}}},
+		}}},
+		Sort: []types.SortCombinations{
+			types.SortOptions{SortOptions: map[string]types.FieldSort{"publish_at": {Order: &sortorder.Desc}}},
+		},
+		Size: &size,
+	}
+	res, err := global.ESClient.Search().
+		Index(elasticsearch.ArticleIndex()).
+		Request(searchReq).
+		SourceIncludes_("title", "slug", "category", "tags", "abstract").
+		Do(context.TODO())
+	if err != nil {
+		return err
+	}
+
+	var articles []other.NewsletterArticle
+	for _, hit := range res.Hits.Hits {
+		var a elasticsearch.Article
+		if err := json.Unmarshal(hit.Source_, &a); err != nil {
+			return err
+		}
+		articles = append(articles, newsletterArticle(*hit.Id_, a))
+	}
+	if len(articles) == 0 {
+		return nil
+	}
+	return global.DB.Create(&database.NewsletterIssue{
+		Template: MailNewsletterDigest,
+		Articles: articles,
+	}).Error
+}
+
+// Dispatch 分批把订阅邮件放入邮件队列，每次最多处理 BatchSize 个订阅者，剩余的订阅者下次继续
+func (newsletterService *NewsletterService) Dispatch() error {
+	if strings.TrimRight(global.Config.Website.SiteURL, "/") == "" {
+		return ErrNewsletterSiteURLRequired
+	}
+	return global.DB.Transaction(func(tx *gorm.DB) error {
+		var issues []database.NewsletterIssue
+		// 多实例部署时通过行锁避免重复发送
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("done = ?", false).
+			Order("id").
+			Find(&issues).Error; err != nil {
+			return err
+		}
+
+		budget := global.Config.Newsletter.BatchSize
+		for _, issue := range issues {
+			if budget <= 0 {
+				break
+			}
+
+			limit := budget
+			var subscribers []database.Subscriber
+			if err := tx.Where("id > ?", issue.Cursor).Order("id").Limit(limit).Find(&subscribers).Error; err != nil {
+				return err
+			}
+			budget -= len(subscribers)
+			// 取到的订阅者不足一批时说明已经处理到最后一个订阅者
+			issue.Done = len(subscribers) < limit
+
+			var sendErr error
+			for _, subscriber := range subscribers {
+				articles := subscriberArticles(subscriber, issue.Articles)
+				if len(articles) > 0 {
+					var unsubscribeURL string
+					if unsubscribeURL, sendErr = newsletterLink("unsubscribe", subscriber.Email); sendErr == nil {
+						sendErr = ServiceGroupApp.MailService.Send(subscriber.Email, issue.Template, map[string]any{
+							"Articles":       articles,
+							"UnsubscribeURL": unsubscribeURL,
+						})
+					}
+					if sendErr != nil {
+						issue.Done = false
+						break
+					}
+					issue.Sent++
+				}
+				issue.Cursor = subscriber.ID
+			}
+
+			if err := tx.Save(&issue).Error; err != nil {
+				return err
+			}
+			if sendErr != nil {
+				return sendErr
+			}
+		}
+		return nil
+	})
+}
+
+// SubscriberList 订阅者列表
+func (newsletterService *NewsletterService) SubscriberList(info request.SubscriberList) (interface{}, int64, error) {
+	db := global.DB.Model(&database.Subscriber{})
+
+	if info.Email != nil {
+		db = db.Where("email LIKE ?", "%"+*info.Email+"%")
+	}
+
+	option := other.MySQLOption{
+		PageInfo: info.PageInfo,
+		Where:    db,
+	}
+
+	return utils.MySQLPagination(&database.Subscriber{}, option)
+}
+
+// SubscriberDelete 删除订阅者
+func (newsletterService *NewsletterService) SubscriberDelete(req request.SubscriberDelete) error {
+	if len(req.IDs) == 0 {
+		return nil
+	}
+	return global.DB.Unscoped().Delete(&database.Subscriber{}, req.IDs).Error
+}
+
+// newsletterLink 生成带签名的订阅确认或退订链接，未配置网站地址时返回 ErrNewsletterSiteURLRequired
+func newsletterLink(action, email string) (string, error) {
+	siteURL := strings.TrimRight(global.Config.Website.SiteURL, "/")
+	if siteURL == "" {
+		return "", ErrNewsletterSiteURLRequired
+	}
+	link, err := url.JoinPath(siteURL, global.Config.System.RouterPrefix, "newsletter", action)
+	if err != nil {
+		return "", err
+	}
+	query := url.Values{
+		"email": {email},
+		"sig":   {utils.HMACSign(global.Config.Newsletter.Secret, action+"|"+email)},
+	}
+	return link + "?" + query.Encode(), nil
+}
+
+// newsletterArticle 生成订阅邮件中的文章摘要
+func newsletterArticle(id string, article elasticsearch.Article) other.NewsletterArticle {
+	return other.NewsletterArticle{
+		ID:       id,
+		Title:    article.Title,
+		Abstract: article.Abstract,
+		Category: article.Category,
+		Tags:     article.Tags,
+		URL:      articlePermalink(strings.TrimRight(global.Config.Website.SiteURL, "/"), id, article.Slug),
+	}
+}
+
+// subscriberArticles 筛选订阅者关注的类别或标签下的文章，没有设置筛选条件时返回全部文章
+func subscriberArticles(subscriber database.Subscriber, articles []other.NewsletterArticle) []other.NewsletterArticle {
+	if len(subscriber.Categories) == 0 && len(subscriber.Tags) == 0 {
+		return articles
+	}
+	var matched []other.NewsletterArticle
+	for _, article := range articles {
+		if slices.Contains(subscriber.Categories, article.Category) || slices.ContainsFunc(article.Tags, func(tag string) bool {
+			return slices.Contains(subscriber.Tags, tag)
+		}) {
+			matched = append(matched, article)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"errors"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"regexp"
+	"server/global"
+	"server/model/appTypes"
+	"server/model/database"
+	"server/model/other"
+	"server/model/request"
+	"server/utils"
+)
+
+type NotificationService struct {
+}
+
+// mentionPattern 匹配评论中的 @用户名
+var mentionPattern = regexp.MustCompile(`@([\p{L}\p{N}_-]{1,32})`)
+
+// Notify 按接收者的偏好发送站内通知和邮件通知，不会通知触发者本人
+func (notificationService *NotificationService) Notify(notifications ...database.Notification) error {
+	var inApp, email []database.Notification
+	for _, n := range notifications {
+		if n.UserUUID == uuid.Nil || n.UserUUID == n.ActorUUID {
+			continue
+		}
+		preference, err := notificationService.preference(n.UserUUID, n.Type)
+		if err != nil {
+			return err
+		}
+		if preference.InApp {
+			inApp = append(inApp, n)
+		}
+		if preference.Email {
+			email = append(email, n)
+		}
+	}
+
+	if len(inApp) > 0 {
+		if err := global.DB.Create(&inApp).Error; err != nil {
+			return err
+		}
+		for _, n := range inApp {
+			ServiceGroupApp.StreamService.Publish(StreamUserChannel(n.UserUUID), appTypes.StreamNotification, n)
+		}
+	}
+	if len(email) > 0 {
+		notificationService.sendEmails(email)
+	}
+	return nil
+}
+
+// MentionedUsers 找出内容中 @ 到的用户
+func (notificationService *NotificationService) MentionedUsers(content string) ([]database.User, error) {
+	var usernames []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			usernames = append(usernames, match[1])
+		}
+	}
+	if len(usernames) == 0 {
+		return nil, nil
+	}
+
+	var users []database.User
+	if err := global.DB.Select("uuid", "username").Where("username IN ?", usernames).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// NotificationList 获取用户的通知列表
+func (notificationService *NotificationService) NotificationList(info request.NotificationList) (interface{}, int64, error) {
+	db := global.DB.Model(&database.Notification{}).Where("user_uuid = ?", info.UserUUID)
+
+	if info.Unread {
+		db = db.Where("is_read = ?", false)
+	}
+
+	option := other.MySQLOption{
+		PageInfo: info.PageInfo,
+		Where:    db,
+	}
+
+	list, total, err := utils.MySQLPagination(&database.Notification{}, option)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 只查询触发者的公开信息
+	var actorUUIDs []uuid.UUID
+	for _, n := range list {
+		actorUUIDs = append(actorUUIDs, n.ActorUUID)
+	}
+	if len(actorUUIDs) > 0 {
+		var actors []database.User
+		if err := global.DB.Select("uuid, username, avatar").Where("uuid IN ?", actorUUIDs).Find(&actors).Error; err != nil {
+			return nil, 0, err
+		}
+		actorMap := make(map[uuid.UUID]database.User, len(actors))
+		for _, actor := range actors {
+			actorMap[actor.UUID] = actor
+		}
+		for i := range list {
+			list[i].Actor = actorMap[list[i].ActorUUID]
+		}
+	}
+	return list, total, nil
+}
+
+// NotificationUnread 获取用户的未读通知数
+func (notificationService *NotificationService) NotificationUnread(userUUID uuid.UUID) (int64, error) {
+	var count int64
+	err := global.DB.Model(&database.Notification{}).Where("user_uuid = ? AND is_read = ?", userUUID, false).Count(&count).Error
+	return count, err
+}
+
+// NotificationRead 将通知标记为已读
+func (notificationService *NotificationService) NotificationRead(req request.NotificationRead) error {
+	db := global.DB.Model(&database.Notification{}).Where("user_uuid = ? AND is_read = ?", req.UserUUID, false)
+	if !req.All {
+		if len(req.IDs) == 0 {
+			return nil
+		}
+		db = db.Where("id IN ?", req.IDs)
+	}
+	return db.Update("is_read", true).Error
+}
+
+// NotificationPreferences 获取用户对每种通知的偏好
+func (notificationService *NotificationService) NotificationPreferences(userUUID uuid.UUID) ([]database.NotificationPreference, error) {
+	preferences := make([]database.NotificationPreference, 0, len(appTypes.NotificationTypes))
+	for _, notificationType := range appTypes.NotificationTypes {
+		preference, err := notificationService.preference(userUUID, notificationType)
+		if err != nil {
+			return nil, err
+		}
+		preferences = append(preferences, preference)
+	}
+	return preferences, nil
+}
+
+// NotificationPreferenceUpdate 更新用户的通知偏好
+func (notificationService *NotificationService) NotificationPreferenceUpdate(req request.NotificationPreferenceUpdate) error {
+	preferences := make([]database.NotificationPreference, 0, len(req.Preferences))
+	for _, p := range req.Preferences {
+		preferences = append(preferences, database.NotificationPreference{
+			UserUUID: req.UserUUID,
+			Type:     p.Type,
+			InApp:    p.InApp,
+			Email:    p.Email,
+		})
+	}
+	return global.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_uuid"}, {Name: "type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"in_app", "email"}),
+	}).Create(&preferences).Error
+}
+
+// preference 获取用户对某种通知的偏好，没有设置时使用默认偏好
+func (notificationService *NotificationService) preference(userUUID uuid.UUID, notificationType appTypes.NotificationType) (database.NotificationPreference, error) {
+	preference := database.NotificationPreference{UserUUID: userUUID, Type: notificationType, InApp: true}
+	err := global.DB.Where("user_uuid = ? AND type = ?", userUUID, notificationType).Take(&preference).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return database.NotificationPreference{}, err
+	}
+	return preference, nil
+}
+
+// sendEmails 将邮件通知放入发送队列，失败只记录日志
+func (notificationService *NotificationService) sendEmails(notifications []database.Notification) {
+	for _, n := range notifications {
+		var user database.User
+		if err := global.DB.Select("email").Where("uuid = ?", n.UserUUID).Take(&user).Error; err != nil || user.Email == "" {
+			continue
+		}
+		if err := ServiceGroupApp.MailService.Send(user.Email, MailNotification(n.Type), map[string]any{"Content": n.Content}); err != nil {
+			global.Log.Error("Failed to queue notification email:", zap.Error(err))
+		}
+	}
+}
+
+// notificationSnippet 截取通知的内容摘要
+func notificationSnippet(content string) string {
+	runes := []rune(content)
+	if len(runes) > 100 {
+		return string(runes[:100]) + "..."
+	}
+	return content
+}
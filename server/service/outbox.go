@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/update"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/scriptlanguage"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"net/http"
+	"server/global"
+	"server/model/appTypes"
+	"server/model/database"
+	"server/model/elasticsearch"
+	"server/model/other"
+	"server/model/request"
+	"server/utils"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	outboxBatchSize   = 200              // 每次投递的最大事件数
+	outboxMaxAttempts = 10               // 超过该重试次数后标记为失败
+	outboxMaxBackoff  = time.Hour        // 最大重试间隔
+	outboxTimeout     = 10 * time.Second // 单个 ES 操作的超时时间
+	outboxBudget      = time.Minute      // 每次投递的最长时间，保证在锁过期之前结束
+	outboxLock        = "es:outbox:dispatch"
+	outboxLockTTL     = 5 * time.Minute
+)
+
+// outboxNotify 通知本实例的投递协程有新写入的事件，多次通知会合并为一次投递
+var outboxNotify = make(chan struct{}, 1)
+
+type OutboxService struct {
+}
+
+// Index 在事务中记录索引文档的操作
+func (outboxService *OutboxService) Index(tx *gorm.DB, index, id string, doc any) error {
+	payload, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return tx.Create(database.NewEsOutbox(appTypes.OutboxIndex, index, id, string(payload))).Error
+}
+
+// Update 在事务中记录局部更新文档的操作
+func (outboxService *OutboxService) Update(tx *gorm.DB, index, id string, doc any) error {
+	payload, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return tx.Create(database.NewEsOutbox(appTypes.OutboxUpdate, index, id, string(payload))).Error
+}
+
+// Script 在事务中记录使用脚本更新文档的操作
+func (outboxService *OutboxService) Script(tx *gorm.DB, index, id, source string) error {
+	return tx.Create(database.NewEsOutbox(appTypes.OutboxScript, index, id, source)).Error
+}
+
+// Delete 在事务中记录删除文档的操作
+func (outboxService *OutboxService) Delete(tx *gorm.DB, index string, ids ...string) error {
+	for _, id := range ids {
+		if err := tx.Create(database.NewEsOutbox(appTypes.OutboxDelete, index, id, "")).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run 投递协程，收到新事件的通知后在请求之外投递，等待重试的事件由定时任务投递
+func (outboxService *OutboxService) Run() {
+	for range outboxNotify {
+		for {
+			ok, err := outboxService.dispatch()
+			if err != nil {
+				global.Log.Error("Failed to dispatch ES outbox events:", zap.Error(err))
+			}
+			if ok || err != nil {
+				break
+			}
+			// 其他实例或定时任务正在投递，稍后重试，避免新事件等到下一次定时任务
+			time.Sleep(time.Second)
+		}
+	}
+}
+
+// Dispatch 投递到期的事件，其他实例正在投递时直接返回
+func (outboxService *OutboxService) Dispatch() error {
+	_, err := outboxService.dispatch()
+	return err
+}
+
+// Pause 暂停投递，等待正在进行的投递结束后返回恢复投递的函数，暂停期间写入的事件会保留在发件箱中
+// ttl 为暂停的最长时间，超时后其他实例会恢复投递
+func (outboxService *OutboxService) Pause(ttl time.Duration) (func(), error) {
+	deadline := time.Now().Add(outboxLockTTL)
+	for {
+		unlock, ok, err := utils.Lock(outboxLock, ttl)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return func() {
+				unlock()
+				outboxService.notify()
+			}, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, errors.New("timed out waiting for the outbox dispatcher to stop")
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// Replay 暂停投递期间把所有可投递的事件投递到 indices 指定的索引，例如重建索引时投递到尚未切换别名的新索引
+// 调用方需要先调用 Pause
+func (outboxService *OutboxService) Replay(indices map[string]string) error {
+	for {
+		more, err := outboxService.dispatchBatch(indices)
+		if err != nil || !more {
+			return err
+		}
+	}
+}
+
+// dispatch 获取投递锁后按写入顺序投递事件，直到没有可投递的事件或超出时间限制，未获取到锁时 ok 为 false
+// 同一时间只有一个实例投递，保证同一文档的事件按顺序执行
+func (outboxService *OutboxService) dispatch() (ok bool, err error) {
+	unlock, ok, err := utils.Lock(outboxLock, outboxLockTTL)
+	if err != nil || !ok {
+		return false, err
+	}
+	defer unlock()
+
+	deadline := time.Now().Add(outboxBudget)
+	for time.Now().Before(deadline) {
+		more, err := outboxService.dispatchBatch(nil)
+		if err != nil || !more {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// dispatchBatch 投递一批事件，成功的事件会被删除，失败的事件按指数退避重试，调用方需要持有 outboxLock
+// 同一文档存在等待重试或投递失败的事件时，该文档后续的事件都不会投递，直到前面的事件投递成功或被人工处理
+// indices 用于把事件投递到指定的索引而不是事件记录的索引，返回是否可能还有待投递的事件
+func (outboxService *OutboxService) dispatchBatch(indices map[string]string) (bool, error) {
+	now := time.Now()
+	var events []database.EsOutbox
+	if err := global.DB.
+		Where("status = ?", appTypes.OutboxPending).
+		Where("NOT EXISTS (SELECT 1 FROM es_outboxes AS prev WHERE prev.`index` = es_outboxes.`index` AND prev.doc_id = es_outboxes.doc_id AND prev.id <= es_outboxes.id AND (prev.status = ? OR prev.next_retry_at > ?))", appTypes.OutboxFailed, now).
+		Order("id").
+		Limit(outboxBatchSize).
+		Find(&events).Error; err != nil {
+		return false, err
+	}
+
+	blocked := make(map[string]bool)
+	refreshed := make(map[string]bool)
+	changed := make(map[string]bool)
+	for _, event := range events {
+		key := event.Index + "/" + event.DocID
+		if blocked[key] {
+			continue
+		}
+
+		index := event.Index
+		if target, ok := indices[index]; ok {
+			index = target
+		}
+		if err := outboxService.deliver(index, event); err != nil {
+			blocked[key] = true
+			event.Attempts++
+			status := appTypes.OutboxPending
+			if event.Attempts >= outboxMaxAttempts {
+				status = appTypes.OutboxFailed
+			}
+			global.Log.Warn("Failed to deliver ES outbox event",
+				zap.Uint("id", event.ID),
+				zap.String("doc_id", event.DocID),
+				zap.Int("attempts", event.Attempts),
+				zap.Error(err))
+			if err := global.DB.Model(&event).Updates(map[string]any{
+				"status":        status,
+				"attempts":      event.Attempts,
+				"next_retry_at": now.Add(outboxBackoff(event.Attempts)),
+				"last_error":    err.Error(),
+			}).Error; err != nil {
+				return false, err
+			}
+			continue
+		}
+
+		refreshed[index] = true
+		changed[event.Index] = true
+		if err := global.DB.Unscoped().Delete(&event).Error; err != nil {
+			return false, err
+		}
+	}
+
+	// 整批投递完成后再刷新索引，使写入对搜索可见
+	if len(refreshed) > 0 {
+		var names []string
+		for name := range refreshed {
+			names = append(names, name)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), outboxTimeout)
+		defer cancel()
+		if _, err := global.ESClient.Indices.Refresh().Index(strings.Join(names, ",")).Do(ctx); err != nil {
+			global.Log.Warn("Failed to refresh ES indices:", zap.Strings("indices", names), zap.Error(err))
+		}
+	}
+	// 文章的变化对搜索可见后再使相关文章缓存失效，否则投递之前的请求会重新缓存旧的结果
+	if changed[elasticsearch.ArticleIndex()] {
+		if err := ServiceGroupApp.ArticleService.ClearRelated(); err != nil {
+			global.Log.Warn("Failed to clear related article cache:", zap.Error(err))
+		}
+	}
+	return len(events) == outboxBatchSize, nil
+}
+
+// OutboxList 获取投递失败或正在重试的事件
+func (outboxService *OutboxService) OutboxList(info request.OutboxList) (interface{}, int64, error) {
+	db := global.DB.Where("status = ? OR attempts > 0", appTypes.OutboxFailed)
+	if info.Status != nil {
+		db = global.DB.Where("status = ? AND attempts > 0", *info.Status)
+	}
+	if info.DocID != nil {
+		db = db.Where("doc_id = ?", *info.DocID)
+	}
+
+	option := other.MySQLOption{
+		PageInfo: info.PageInfo,
+		Where:    db,
+		Order:    "id asc",
+	}
+	return utils.MySQLPagination(&database.EsOutbox{}, option)
+}
+
+// OutboxRetry 将事件重新放回投递队列并通知投递协程投递
+func (outboxService *OutboxService) OutboxRetry(req request.OutboxRetry) error {
+	if len(req.IDs) == 0 {
+		return nil
+	}
+	if err := global.DB.Model(&database.EsOutbox{}).Where("id IN ?", req.IDs).Updates(map[string]any{
+		"status":        appTypes.OutboxPending,
+		"attempts":      0,
+		"next_retry_at": time.Now(),
+	}).Error; err != nil {
+		return err
+	}
+	outboxService.notify()
+	return nil
+}
+
+// notify 通知投递协程投递新的事件，不等待投递完成
+func (outboxService *OutboxService) notify() {
+	select {
+	case outboxNotify <- struct{}{}:
+	default:
+	}
+}
+
+// deliver 在指定的索引上执行单个 ES 操作
+func (outboxService *OutboxService) deliver(index string, event database.EsOutbox) error {
+	ctx, cancel := context.WithTimeout(context.Background(), outboxTimeout)
+	defer cancel()
+
+	var err error
+	switch event.Operation {
+	case appTypes.OutboxIndex:
+		_, err = global.ESClient.Index(index).Id(event.DocID).Raw(strings.NewReader(event.Payload)).Do(ctx)
+	case appTypes.OutboxUpdate:
+		_, err = global.ESClient.Update(index, event.DocID).Request(&update.Request{Doc: json.RawMessage(event.Payload)}).Do(ctx)
+	case appTypes.OutboxScript:
+		// 同一文档的事件按顺序投递，文档记录最近一次执行的事件 ID，ES 已经执行但响应超时等情况下重试时不会重复执行
+		source := "if (ctx._source.outbox_seq != null && ctx._source.outbox_seq >= params.outbox_id) { ctx.op = 'noop' } else { ctx._source.outbox_seq = params.outbox_id; " + event.Payload + " }"
+		script := types.Script{
+			Source: &source,
+			Lang:   &scriptlanguage.Painless,
+			Params: map[string]json.RawMessage{"outbox_id": json.RawMessage(strconv.FormatUint(uint64(event.ID), 10))},
+		}
+		_, err = global.ESClient.Update(index, event.DocID).Script(&script).Do(ctx)
+	case appTypes.OutboxDelete:
+		_, err = global.ESClient.Delete(index, event.DocID).Do(ctx)
+		// 文档已不存在时视为删除成功
+		var esErr *types.ElasticsearchError
+		if errors.As(err, &esErr) && esErr.Status == http.StatusNotFound {
+			err = nil
+		}
+	default:
+		err = fmt.Errorf("unknown outbox operation: %s", event.Operation)
+	}
+	return err
+}
+
+// outboxBackoff 计算第 attempts 次失败后的重试间隔
+func outboxBackoff(attempts int) time.Duration {
+	backoff := time.Second << attempts
+	if backoff <= 0 || backoff > outboxMaxBackoff {
+		return outboxMaxBackoff
+	}
+	return backoff
+}
+
+// outboxTransaction 在事务中执行 fn，提交后通知投递协程投递事务中记录的 ES 操作，投递失败的操作由定时任务重试
+func outboxTransaction(fn func(tx *gorm.DB) error) error {
+	if err := global.DB.Transaction(fn); err != nil {
+		return err
+	}
+	ServiceGroupApp.OutboxService.notify()
+	return nil
+}
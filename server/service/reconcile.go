@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"gorm.io/gorm"
+	"server/global"
+	"server/model/appTypes"
+	"server/model/database"
+	"server/model/elasticsearch"
+	"server/model/other"
+	"server/utils"
+	"sort"
+)
+
+type ReconcileService struct {
+}
+
+// articleCounter 文章的计数
+type articleCounter struct {
+	ArticleID string
+	Number    int
+}
+
+// Reconcile 根据收藏表、评论表和 ES 中的文章重新计算收藏数、评论数以及类别和标签的文章数，返回所有偏差
+// fix 为 true 时同时修正偏差，文章的计数通过发件箱更新，计数为 0 的类别和标签会被删除
+func (reconcileService *ReconcileService) Reconcile(fix bool) ([]other.CounterDrift, error) {
+	likes, err := reconcileService.countByArticle(global.DB.Model(&database.ArticleLike{}))
+	if err != nil {
+		return nil, err
+	}
+	comments, err := reconcileService.countByArticle(global.DB.Model(&database.Comment{}).Where("status = ? AND deleted = ?", appTypes.CommentApproved, false))
+	if err != nil {
+		return nil, err
+	}
+
+	var drifts []other.CounterDrift
+	articleDrifts := make(map[string]map[string]int)
+	categories := make(map[string]int)
+	tags := make(map[string]int)
+
+	query := types.Query{MatchAll: &types.MatchAllQuery{}}
+	err = utils.EsScroll(context.TODO(), elasticsearch.ArticleIndex(), &query, []string{"category", "tags", "likes", "comments"}, func(hits []types.Hit) error {
+		for _, hit := range hits {
+			var a elasticsearch.Article
+			if err := json.Unmarshal(hit.Source_, &a); err != nil {
+				return err
+			}
+			id := *hit.Id_
+			if a.Category != "" {
+				categories[a.Category]++
+			}
+			for _, tag := range a.Tags {
+				tags[tag]++
+			}
+			for _, d := range []other.CounterDrift{
+				{Kind: "likes", Key: id, Recorded: a.Likes, Actual: likes[id]},
+				{Kind: "comments", Key: id, Recorded: a.Comments, Actual: comments[id]},
+			} {
+				if d.Recorded == d.Actual {
+					continue
+				}
+				drifts = append(drifts, d)
+				if articleDrifts[id] == nil {
+					articleDrifts[id] = make(map[string]int)
+				}
+				articleDrifts[id][d.Kind] = d.Actual
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 还有未投递的发件箱事件的文章，ES 中的计数稍后才会被这些事件更新，此时的偏差不可信，留到下次校对
+	busy, err := reconcileService.outboxDocs(global.DB, articleDrifts)
+	if err != nil {
+		return nil, err
+	}
+	drifts = skipArticleDrifts(drifts, articleDrifts, busy)
+
+	var articleCategories []database.ArticleCategory
+	if err := global.DB.Find(&articleCategories).Error; err != nil {
+		return nil, err
+	}
+	recordedCategories := make(map[string]int)
+	for _, c := range articleCategories {
+		recordedCategories[c.Category] = c.Number
+	}
+	categoryDrifts := diffCounters("category", recordedCategories, categories)
+
+	var articleTags []database.ArticleTag
+	if err := global.DB.Find(&articleTags).Error; err != nil {
+		return nil, err
+	}
+	recordedTags := make(map[string]int)
+	for _, t := range articleTags {
+		recordedTags[t.Tag] = t.Number
+	}
+	tagDrifts := diffCounters("tag", recordedTags, tags)
+
+	drifts = append(drifts, categoryDrifts...)
+	drifts = append(drifts, tagDrifts...)
+	if !fix || len(drifts) == 0 {
+		return drifts, nil
+	}
+
+	var skipped map[string]bool
+	err = outboxTransaction(func(tx *gorm.DB) error {
+		// 校对期间可能有新的收藏或评论，在事务中重新检查，计数已经变化的文章留到下次校对
+		if skipped, err = reconcileService.outboxDocs(tx, articleDrifts); err != nil {
+			return err
+		}
+		ids := make([]string, 0, len(articleDrifts))
+		for id := range articleDrifts {
+			ids = append(ids, id)
+		}
+		likes, err := reconcileService.countByArticle(tx.Model(&database.ArticleLike{}).Where("article_id IN ?", ids))
+		if err != nil {
+			return err
+		}
+		comments, err := reconcileService.countByArticle(tx.Model(&database.Comment{}).Where("article_id IN ? AND status = ? AND deleted = ?", ids, appTypes.CommentApproved, false))
+		if err != nil {
+			return err
+		}
+
+		for id, fields := range articleDrifts {
+			if skipped[id] {
+				continue
+			}
+			if actual, ok := fields["likes"]; ok && actual != likes[id] {
+				skipped[id] = true
+				continue
+			}
+			if actual, ok := fields["comments"]; ok && actual != comments[id] {
+				skipped[id] = true
+				continue
+			}
+			// 写入绝对值，发件箱按顺序投递，之后的增量事件会在此基础上累加
+			if err := ServiceGroupApp.OutboxService.Update(tx, elasticsearch.ArticleIndex(), id, fields); err != nil {
+				return err
+			}
+		}
+		for _, d := range categoryDrifts {
+			if err := saveCounter(tx, &database.ArticleCategory{Category: d.Key, Number: d.Actual}, d); err != nil {
+				return err
+			}
+		}
+		for _, d := range tagDrifts {
+			if err := saveCounter(tx, &database.ArticleTag{Tag: d.Key, Number: d.Actual}, d); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return skipArticleDrifts(drifts, articleDrifts, skipped), nil
+}
+
+// outboxDocs 找出还有未投递或投递失败的发件箱事件的文章
+func (reconcileService *ReconcileService) outboxDocs(db *gorm.DB, articles map[string]map[string]int) (map[string]bool, error) {
+	docs := make(map[string]bool)
+	if len(articles) == 0 {
+		return docs, nil
+	}
+	ids := make([]string, 0, len(articles))
+	for id := range articles {
+		ids = append(ids, id)
+	}
+	var busy []string
+	if err := db.Model(&database.EsOutbox{}).
+		Where("`index` = ? AND doc_id IN ?", elasticsearch.ArticleIndex(), ids).
+		Distinct().
+		Pluck("doc_id", &busy).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range busy {
+		docs[id] = true
+	}
+	return docs, nil
+}
+
+// skipArticleDrifts 从偏差中去掉被跳过的文章，被跳过的文章也不再修正
+func skipArticleDrifts(drifts []other.CounterDrift, articles map[string]map[string]int, skip map[string]bool) []other.CounterDrift {
+	if len(skip) == 0 {
+		return drifts
+	}
+	kept := drifts[:0]
+	for _, d := range drifts {
+		if (d.Kind == "likes" || d.Kind == "comments") && skip[d.Key] {
+			continue
+		}
+		kept = append(kept, d)
+	}
+	for id := range skip {
+		delete(articles, id)
+	}
+	return kept
+}
+
+// countByArticle 按文章统计查询结果中的记录数
+func (reconcileService *ReconcileService) countByArticle(db *gorm.DB) (map[string]int, error) {
+	var rows []articleCounter
+	if err := db.Select("article_id, count(*) AS number").Group("article_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.ArticleID] = row.Number
+	}
+	return counts, nil
+}
+
+// diffCounters 比较记录的计数与实际计数，按名称排序返回偏差，计数为 0 的残留记录也视为偏差
+func diffCounters(kind string, recorded, actual map[string]int) []other.CounterDrift {
+	var drifts []other.CounterDrift
+	for key, number := range recorded {
+		if actual[key] != number || number == 0 {
+			drifts = append(drifts, other.CounterDrift{Kind: kind, Key: key, Recorded: number, Actual: actual[key]})
+		}
+	}
+	for key, number := range actual {
+		if _, ok := recorded[key]; !ok {
+			drifts = append(drifts, other.CounterDrift{Kind: kind, Key: key, Recorded: 0, Actual: number})
+		}
+	}
+	sort.Slice(drifts, func(i, j int) bool { return drifts[i].Key < drifts[j].Key })
+	return drifts
+}
+
+// saveCounter 修正类别或标签的计数，实际计数为 0 时删除该记录
+func saveCounter(tx *gorm.DB, value any, drift other.CounterDrift) error {
+	if drift.Actual == 0 {
+		return tx.Delete(value).Error
+	}
+	return tx.Save(value).Error
+}
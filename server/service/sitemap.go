@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/go-redis/redis"
+	"net/url"
+	"server/global"
+	"server/model/database"
+	"server/model/elasticsearch"
+	"server/model/other"
+	"server/utils"
+	"strings"
+	"time"
+)
+
+const (
+	sitemapKey      = "sitemap"     // Redis 中缓存站点地图的哈希表
+	sitemapIndex    = "sitemap.xml" // 站点地图入口文件名
+	sitemapMaxURLs  = 50000         // 单个站点地图最多包含的页面数量
+	sitemapXmlns    = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	sitemapCacheTTL = 25 * time.Hour
+)
+
+// ErrSiteURLRequired 未配置网站地址时不生成站点地图，避免根据请求头生成的链接被缓存
+var ErrSiteURLRequired = errors.New("website.site_url is required to generate the sitemap")
+
+type SitemapService struct {
+}
+
+// GetSitemap 从缓存中获取指定的站点地图文件，缓存不存在时重新生成
+func (sitemapService *SitemapService) GetSitemap(name string) ([]byte, error) {
+	result, err := global.Redis.HGet(sitemapKey, name).Result()
+	if err == nil {
+		return []byte(result), nil
+	}
+	if !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	// 缓存中没有站点地图时重新生成
+	exists, _ := global.Redis.Exists(sitemapKey).Result()
+	if exists > 0 {
+		return nil, errors.New("sitemap not found")
+	}
+	files, err := sitemapService.RefreshSitemap()
+	if err != nil {
+		return nil, err
+	}
+	file, ok := files[name]
+	if !ok {
+		return nil, errors.New("sitemap not found")
+	}
+	return file, nil
+}
+
+// RefreshSitemap 使用配置的网站地址重新生成站点地图并写入 Redis 缓存
+func (sitemapService *SitemapService) RefreshSitemap() (map[string][]byte, error) {
+	siteURL := strings.TrimRight(global.Config.Website.SiteURL, "/")
+	if siteURL == "" {
+		return nil, ErrSiteURLRequired
+	}
+	files, err := sitemapService.GenerateSitemap(siteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]interface{}, len(files))
+	for name, file := range files {
+		fields[name] = file
+	}
+	pipe := global.Redis.TxPipeline()
+	pipe.Del(sitemapKey)
+	pipe.HMSet(sitemapKey, fields)
+	pipe.Expire(sitemapKey, sitemapCacheTTL)
+	if _, err := pipe.Exec(); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// GenerateSitemap 生成站点地图，包含首页、所有已发布的文章以及类别和标签列表页
+// 页面数量超过 50000 时拆分为多个站点地图，并生成站点地图索引作为入口
+func (sitemapService *SitemapService) GenerateSitemap(siteURL string) (map[string][]byte, error) {
+	urls := []other.SitemapURL{{Loc: siteURL + "/", ChangeFreq: "daily"}}
+
+	articleURLs, err := sitemapService.articleURLs(siteURL)
+	if err != nil {
+		return nil, err
+	}
+	urls = append(urls, articleURLs...)
+
+	var categories []database.ArticleCategory
+	if err := global.DB.Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	for _, category := range categories {
+		urls = append(urls, other.SitemapURL{Loc: siteURL + "/article?category=" + url.QueryEscape(category.Category), ChangeFreq: "weekly"})
+	}
+
+	var tags []database.ArticleTag
+	if err := global.DB.Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	for _, tag := range tags {
+		urls = append(urls, other.SitemapURL{Loc: siteURL + "/article?tag=" + url.QueryEscape(tag.Tag), ChangeFreq: "weekly"})
+	}
+
+	files := make(map[string][]byte)
+
+	// 页面数量未超过上限时只生成一个站点地图
+	if len(urls) <= sitemapMaxURLs {
+		file, err := marshalSitemap(other.SitemapURLSet{Xmlns: sitemapXmlns, URLs: urls})
+		if err != nil {
+			return nil, err
+		}
+		files[sitemapIndex] = file
+		return files, nil
+	}
+
+	// 拆分为多个站点地图，并生成站点地图索引
+	index := other.SitemapIndex{Xmlns: sitemapXmlns}
+	lastMod := time.Now().Format(time.RFC3339)
+	for i := 0; i*sitemapMaxURLs < len(urls); i++ {
+		end := min((i+1)*sitemapMaxURLs, len(urls))
+		name := fmt.Sprintf("sitemap-%d.xml", i+1)
+		file, err := marshalSitemap(other.SitemapURLSet{Xmlns: sitemapXmlns, URLs: urls[i*sitemapMaxURLs : end]})
+		if err != nil {
+			return nil, err
+		}
+		files[name] = file
+		index.Sitemaps = append(index.Sitemaps, other.SitemapEntry{Loc: siteURL + "/sitemap/" + name, LastMod: lastMod})
+	}
+	file, err := marshalSitemap(index)
+	if err != nil {
+		return nil, err
+	}
+	files[sitemapIndex] = file
+	return files, nil
+}
+
+// Robots 获取 robots.txt 的内容，未配置时允许抓取全部页面并指向站点地图
+func (sitemapService *SitemapService) Robots(siteURL string) string {
+	if global.Config.Website.Robots != "" {
+		return global.Config.Website.Robots
+	}
+	// 未配置网站地址时不提供站点地图
+	if siteURL == "" {
+		return "User-agent: *\nAllow: /\n"
+	}
+	return "User-agent: *\nAllow: /\n\nSitemap: " + siteURL + "/" + sitemapIndex + "\n"
+}
+
+// ClearSitemap 清除站点地图缓存，下次请求时重新生成
+func (sitemapService *SitemapService) ClearSitemap() error {
+	return global.Redis.Del(sitemapKey).Err()
+}
+
+// articleURLs 使用滚动查询遍历所有已发布的文章，生成文章页面地址
+func (sitemapService *SitemapService) articleURLs(siteURL string) ([]other.SitemapURL, error) {
+	var urls []other.SitemapURL
+	appendHits := func(hits []types.Hit) error {
+		for _, hit := range hits {
+			var a elasticsearch.Article
+			if err := json.Unmarshal(hit.Source_, &a); err != nil {
+				return err
+			}
+			var lastMod string
+			if t := utils.ParseDateTime(a.UpdatedAt); !t.IsZero() {
+				lastMod = t.Format(time.RFC3339)
+			}
+			urls = append(urls, other.SitemapURL{Loc: articlePermalink(siteURL, *hit.Id_, a.Slug), LastMod: lastMod})
+		}
+		return nil
+	}
+
+	query := publishedQuery()
+	if err := utils.EsScroll(context.TODO(), elasticsearch.ArticleIndex(), &query, []string{"updated_at", "slug"}, appendHits); err != nil {
+		return nil, err
+	}
+	return urls, nil
+}
+
+// marshalSitemap 将站点地图序列化为 XML
+func marshalSitemap(v any) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
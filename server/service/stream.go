@@ -0,0 +1,134 @@
+package service
+
+import (
+	"encoding/json"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+	"server/global"
+	"server/model/appTypes"
+	"server/model/other"
+	"sync"
+	"time"
+)
+
+type StreamService struct {
+}
+
+// streamPattern 所有推送频道的前缀，每个实例只订阅一次，再在本地分发给各个连接
+const streamPattern = "stream:*"
+
+// streamBuffer 每个连接缓存的消息数，客户端消费过慢时丢弃新消息
+const streamBuffer = 16
+
+// streamMaxBackoff 订阅断开后重试的最长间隔
+const streamMaxBackoff = 30 * time.Second
+
+// StreamAdminChannel 管理员事件频道
+const StreamAdminChannel = "stream:admin"
+
+// StreamArticleChannel 文章评论频道
+func StreamArticleChannel(articleID string) string {
+	return "stream:article:" + articleID
+}
+
+// StreamUserChannel 用户个人通知频道
+func StreamUserChannel(userUUID uuid.UUID) string {
+	return "stream:user:" + userUUID.String()
+}
+
+// streamHub 本实例上的订阅者，按频道分组
+type streamHub struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[chan other.StreamMessage]struct{}
+}
+
+var streams = &streamHub{subscribers: make(map[string]map[chan other.StreamMessage]struct{})}
+
+var startStreams sync.Once
+
+// run 订阅 Redis 频道并把收到的消息分发给本实例的订阅者
+// 连接断开时记录日志，go-redis 在下一次读取时会重新连接并重新订阅，连续失败时逐渐延长重试间隔
+func (h *streamHub) run() {
+	pubsub := global.Redis.PSubscribe(streamPattern)
+	defer pubsub.Close()
+
+	failures := 0
+	for {
+		msg, err := pubsub.ReceiveMessage()
+		if err != nil {
+			failures++
+			global.Log.Warn("Stream subscription interrupted, reconnecting:", zap.Int("failures", failures), zap.Error(err))
+			time.Sleep(min(time.Duration(failures)*time.Second, streamMaxBackoff))
+			continue
+		}
+		if failures > 0 {
+			global.Log.Info("Stream subscription restored")
+			failures = 0
+		}
+
+		var message other.StreamMessage
+		if err := json.Unmarshal([]byte(msg.Payload), &message); err != nil {
+			global.Log.Error("Failed to decode stream message:", zap.Error(err))
+			continue
+		}
+
+		h.mu.RLock()
+		for ch := range h.subscribers[msg.Channel] {
+			select {
+			case ch <- message:
+			default:
+			}
+		}
+		h.mu.RUnlock()
+	}
+}
+
+// Start 启动本实例的推送分发协程，在服务启动时调用，多次调用只会启动一次
+func (streamService *StreamService) Start() {
+	startStreams.Do(func() {
+		go streams.run()
+	})
+}
+
+// Publish 向指定频道发布一条推送消息，发布失败只记录日志，不影响业务
+func (streamService *StreamService) Publish(channel string, event appTypes.StreamEvent, data interface{}) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		global.Log.Error("Failed to encode stream message:", zap.Error(err))
+		return
+	}
+	payload, err := json.Marshal(other.StreamMessage{Event: event, Data: raw})
+	if err != nil {
+		global.Log.Error("Failed to encode stream message:", zap.Error(err))
+		return
+	}
+	if err := global.Redis.Publish(channel, payload).Err(); err != nil {
+		global.Log.Error("Failed to publish stream message:", zap.String("channel", channel), zap.Error(err))
+	}
+}
+
+// Subscribe 订阅若干频道，返回消息通道和取消订阅的函数
+func (streamService *StreamService) Subscribe(channels ...string) (<-chan other.StreamMessage, func()) {
+	streamService.Start()
+
+	ch := make(chan other.StreamMessage, streamBuffer)
+	streams.mu.Lock()
+	for _, channel := range channels {
+		if streams.subscribers[channel] == nil {
+			streams.subscribers[channel] = make(map[chan other.StreamMessage]struct{})
+		}
+		streams.subscribers[channel][ch] = struct{}{}
+	}
+	streams.mu.Unlock()
+
+	return ch, func() {
+		streams.mu.Lock()
+		defer streams.mu.Unlock()
+		for _, channel := range channels {
+			delete(streams.subscribers[channel], ch)
+			if len(streams.subscribers[channel]) == 0 {
+				delete(streams.subscribers, channel)
+			}
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-redis/redis"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"server/global"
+	"server/model/appTypes"
+	"server/model/database"
+	"server/model/other"
+	"server/model/request"
+	"server/model/response"
+	"server/utils"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type TrafficService struct {
+}
+
+const (
+	trafficKeyPrefix    = "traffic:"      // 每小时流量数据的键前缀
+	trafficHoursKey     = "traffic:hours" // 尚未汇总到 MySQL 的小时键集合
+	trafficHourLayout   = "2006010215"    // 小时键的时间格式
+	trafficHourTTL      = 48 * time.Hour  // 小时数据的过期时间，汇总失败时最多保留两天
+	trafficFlushing     = ":flushing"     // 正在汇总的小时数据的键后缀
+	trafficFlushLock    = "traffic:flush" // 汇总流量数据的锁
+	trafficFlushLockTTL = 5 * time.Minute // 汇总流量数据的锁的过期时间
+	trafficDirect       = "直接访问"          // 没有来源时的取值
+	trafficUnknown      = "未知"            // 无法识别时的取值
+	trafficDomestic     = "中国"            // 国内访客的国家
+	trafficOverseas     = "境外"            // 境外访客的国家和省份
+	trafficValueMaxLen  = 255             // 维度取值的最大长度
+	trafficTopSize      = 10              // 每个维度返回的条数
+	ipRegionTTL         = 24 * time.Hour  // IP 所在地区的缓存时间
+)
+
+// trafficFlushDone 删除已经汇总的数据，汇总期间该小时又有新的请求时保留小时键，下次继续汇总
+var trafficFlushDone = redis.NewScript(`
+redis.call('DEL', KEYS[2])
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	redis.call('SREM', KEYS[3], KEYS[1])
+end
+return 1
+`)
+
+// Record 将一次请求的流量计入当前小时的 Redis 哈希，字段为 维度|取值|hits 和 维度|取值|duration
+func (trafficService *TrafficService) Record(record other.TrafficRecord) error {
+	key := trafficKeyPrefix + time.Now().Format(trafficHourLayout)
+	values := map[appTypes.TrafficDimension]string{
+		appTypes.TrafficPath:     record.Path,
+		appTypes.TrafficReferrer: record.Referrer,
+		appTypes.TrafficCountry:  record.Country,
+		appTypes.TrafficProvince: record.Province,
+		appTypes.TrafficDevice:   record.Device,
+		appTypes.TrafficOS:       record.OS,
+		appTypes.TrafficBrowser:  record.Browser,
+	}
+	if record.Referrer == "" {
+		values[appTypes.TrafficReferrer] = trafficDirect
+	}
+	duration := record.Duration.Milliseconds()
+
+	pipe := global.Redis.TxPipeline()
+	for dimension, value := range values {
+		field := string(dimension) + "|" + trafficValue(value)
+		pipe.HIncrBy(key, field+"|hits", 1)
+		pipe.HIncrBy(key, field+"|duration", duration)
+	}
+	pipe.Expire(key, trafficHourTTL)
+	pipe.SAdd(trafficHoursKey, key)
+	_, err := pipe.Exec()
+	return err
+}
+
+// Region 获取 IP 所在的国家和省份，结果缓存在 Redis 中
+// 高德只能定位国内 IP，对境外 IP 返回空的省份，这些访客的国家和省份都记为境外，查询失败时记为未知
+func (trafficService *TrafficService) Region(ip string) (country, province string) {
+	key := "ip-region-" + ip
+	if region, err := global.Redis.Get(key).Result(); err == nil {
+		if country, province, found := strings.Cut(region, "|"); found {
+			return country, province
+		}
+	}
+
+	country, province = trafficUnknown, trafficUnknown
+	res, err := ServiceGroupApp.GaodeService.GetLocationByIP(ip)
+	// 高德对境外 IP 返回的省份是空数组，解析为字符串时会出现类型错误，其余字段仍然有效
+	var typeErr *json.UnmarshalTypeError
+	if err == nil || errors.As(err, &typeErr) {
+		switch {
+		case res.Status != "1":
+		case res.Province != "":
+			country, province = trafficDomestic, res.Province
+		default:
+			country, province = trafficOverseas, trafficOverseas
+		}
+	}
+	_ = global.Redis.Set(key, country+"|"+province, ipRegionTTL).Err()
+	return country, province
+}
+
+// Flush 将已经结束的小时数据累加到 MySQL 的每日流量统计表
+// 多实例部署时只有一个实例汇总，避免同一小时的数据被重复累加
+func (trafficService *TrafficService) Flush() error {
+	unlock, ok, err := utils.Lock(trafficFlushLock, trafficFlushLockTTL)
+	if err != nil || !ok {
+		return err
+	}
+	defer unlock()
+
+	keys, err := global.Redis.SMembers(trafficHoursKey).Result()
+	if err != nil {
+		return err
+	}
+	sort.Strings(keys)
+
+	current := trafficKeyPrefix + time.Now().Format(trafficHourLayout)
+	for _, key := range keys {
+		if key >= current {
+			continue
+		}
+		if err := trafficService.flushHour(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flushHour 汇总一个小时的数据，成功后删除该小时的键
+// 汇总前先把小时键改名，汇总期间迟到的请求会写入新的小时键，不会在删除时丢失
+func (trafficService *TrafficService) flushHour(key string) error {
+	hour, err := time.ParseInLocation(trafficHourLayout, strings.TrimPrefix(key, trafficKeyPrefix), time.Local)
+	if err != nil {
+		return global.Redis.SRem(trafficHoursKey, key).Err()
+	}
+	date := hour.Format("2006-01-02")
+
+	// 上次汇总中断时先汇总改名后的键，小时键留到下次汇总
+	processing := key + trafficFlushing
+	exists, err := global.Redis.Exists(processing).Result()
+	if err != nil {
+		return err
+	}
+	if exists == 0 {
+		ok, err := global.Redis.Exists(key).Result()
+		if err != nil {
+			return err
+		}
+		if ok == 0 {
+			return global.Redis.SRem(trafficHoursKey, key).Err()
+		}
+		if err := global.Redis.Rename(key, processing).Err(); err != nil {
+			return err
+		}
+	}
+
+	data, err := global.Redis.HGetAll(processing).Result()
+	if err != nil {
+		return err
+	}
+	rows := make(map[string]*database.TrafficDaily)
+	for field, val := range data {
+		dimensionValue, metric, found := cutLast(field, "|")
+		if !found {
+			continue
+		}
+		dimension, value, found := strings.Cut(dimensionValue, "|")
+		if !found {
+			continue
+		}
+		num, _ := strconv.ParseInt(val, 10, 64)
+		row := rows[dimensionValue]
+		if row == nil {
+			row = &database.TrafficDaily{Date: date, Dimension: appTypes.TrafficDimension(dimension), Value: value}
+			rows[dimensionValue] = row
+		}
+		switch metric {
+		case "hits":
+			row.Hits += int(num)
+		case "duration":
+			row.Duration += num
+		}
+	}
+
+	if len(rows) > 0 {
+		list := make([]database.TrafficDaily, 0, len(rows))
+		for _, row := range rows {
+			list = append(list, *row)
+		}
+		if err := global.DB.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "date"}, {Name: "dimension"}, {Name: "value"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"hits":     gorm.Expr("hits + VALUES(hits)"),
+				"duration": gorm.Expr("duration + VALUES(duration)"),
+			}),
+		}).CreateInBatches(&list, 500).Error; err != nil {
+			return err
+		}
+	}
+
+	return trafficFlushDone.Run(&global.Redis, []string{key, processing, trafficHoursKey}).Err()
+}
+
+// TrafficStats 获取最近若干天的访问量以及热门页面、来源、地区和设备分布，数据每小时汇总一次
+func (trafficService *TrafficService) TrafficStats(req request.TrafficStats) (response.TrafficStats, error) {
+	var res response.TrafficStats
+	startDate := time.Now().AddDate(0, 0, -req.Date+1).Format("2006-01-02")
+	db := global.DB.Model(&database.TrafficDaily{}).Where("date >= ?", startDate)
+
+	var total struct {
+		Hits     int
+		Duration int64
+	}
+	if err := db.Session(&gorm.Session{}).Where("dimension = ?", appTypes.TrafficPath).
+		Select("coalesce(sum(hits), 0) AS hits, coalesce(sum(duration), 0) AS duration").Scan(&total).Error; err != nil {
+		return response.TrafficStats{}, err
+	}
+	res.Hits = total.Hits
+	if total.Hits > 0 {
+		res.AvgDuration = total.Duration / int64(total.Hits)
+	}
+
+	for dimension, list := range map[appTypes.TrafficDimension]*[]response.TrafficItem{
+		appTypes.TrafficPath:     &res.Pages,
+		appTypes.TrafficReferrer: &res.Referrers,
+		appTypes.TrafficCountry:  &res.Countries,
+		appTypes.TrafficProvince: &res.Provinces,
+		appTypes.TrafficDevice:   &res.Devices,
+		appTypes.TrafficOS:       &res.OS,
+		appTypes.TrafficBrowser:  &res.Browsers,
+	} {
+		if err := db.Session(&gorm.Session{}).Where("dimension = ?", dimension).
+			Select("value, sum(hits) AS hits, sum(duration) div sum(hits) AS avg_duration").
+			Group("value").Order("hits desc").Limit(trafficTopSize).Scan(list).Error; err != nil {
+			return response.TrafficStats{}, err
+		}
+	}
+	return res, nil
+}
+
+// trafficValue 规范化维度取值，空值记为未知，过长的取值会被截断
+func trafficValue(value string) string {
+	if value == "" {
+		return trafficUnknown
+	}
+	if utf8.RuneCountInString(value) > trafficValueMaxLen {
+		value = string([]rune(value)[:trafficValueMaxLen])
+	}
+	return value
+}
+
+// cutLast 在最后一个分隔符处切分字符串
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
@@ -13,6 +13,7 @@ import (
 	"server/model/request"
 	"server/model/response"
 	"server/utils"
+	"strings"
 	"time"
 )
 
@@ -33,6 +34,7 @@ func (userService *UserService) Register(u database.User) (database.User, error)
 	if err := global.DB.Create(&u).Error; err != nil {
 		return database.User{}, err
 	}
+	ServiceGroupApp.StreamService.Publish(StreamAdminChannel, appTypes.StreamRegister, u)
 
 	return u, nil
 }
@@ -74,6 +76,7 @@ func (userService *UserService) QQLogin(accessTokenResponse other.AccessTokenRes
 		if err := global.DB.Create(&user).Error; err != nil {
 			return database.User{}, err
 		}
+		ServiceGroupApp.StreamService.Publish(StreamAdminChannel, appTypes.StreamRegister, user)
 	}
 
 	return user, nil
@@ -138,6 +141,26 @@ func (userService *UserService) UserChangeInfo(req request.UserChangeInfo) error
 	return global.DB.Model(&user).Updates(req).Error
 }
 
+// ErrPasswordMismatch 当前密码错误
+var ErrPasswordMismatch = errors.New("password does not match the current account")
+
+// UserChangeEmail 修改邮箱，需要校验当前密码，新邮箱不能已被其他账号使用
+func (userService *UserService) UserChangeEmail(req request.UserChangeEmail) error {
+	var user database.User
+	if err := global.DB.Take(&user, req.UserID).Error; err != nil {
+		return err
+	}
+	if ok := utils.BcryptCheck(req.Password, user.Password); !ok {
+		return ErrPasswordMismatch
+	}
+
+	email := strings.ToLower(req.Email)
+	if !errors.Is(global.DB.Where("email = ? AND id <> ?", email, req.UserID).First(&database.User{}).Error, gorm.ErrRecordNotFound) {
+		return errors.New("this email address is already registered")
+	}
+	return global.DB.Model(&user).Update("email", email).Error
+}
+
 func (userService *UserService) UserWeather(ip string) (string, error) {
 	// 从redis中获取天气数据，如果没有数据，则调用高德api进行查询
 	result, err := global.Redis.Get("weather-" + ip).Result()
@@ -0,0 +1,10 @@
+package task
+
+import (
+	"server/service"
+)
+
+// PublishScheduledArticlesSyncTask 将到达发布时间的定时文章切换为已发布状态，并通知订阅者
+func PublishScheduledArticlesSyncTask() error {
+	return service.ServiceGroupApp.ArticleService.PublishScheduled()
+}
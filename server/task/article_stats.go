@@ -0,0 +1,20 @@
+package task
+
+import (
+	"server/service"
+	"time"
+)
+
+// RollupArticleStatsSyncTask 汇总昨天和今天的文章统计数据，并清理过期的访问事件
+func RollupArticleStatsSyncTask() error {
+	articleService := service.ServiceGroupApp.ArticleService
+	now := time.Now()
+	// 浏览量按小时同步，昨天最后一次同步的数据需要在今天汇总
+	if err := articleService.RollupArticleStats(now.AddDate(0, 0, -1)); err != nil {
+		return err
+	}
+	if err := articleService.RollupArticleStats(now); err != nil {
+		return err
+	}
+	return articleService.PurgeArticleVisits()
+}
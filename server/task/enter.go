@@ -14,6 +14,20 @@ func RegisterScheduledTasks(c *cron.Cron) error {
 	}); err != nil {
 		return err
 	}
+	if _, err := c.AddFunc("@hourly", func() {
+		if err := RollupArticleStatsSyncTask(); err != nil {
+			global.Log.Error("Failed to roll up article stats:", zap.Error(err))
+		}
+	}); err != nil {
+		return err
+	}
+	if _, err := c.AddFunc("@hourly", func() {
+		if err := FlushTrafficSyncTask(); err != nil {
+			global.Log.Error("Failed to flush traffic:", zap.Error(err))
+		}
+	}); err != nil {
+		return err
+	}
 	if _, err := c.AddFunc("@hourly", func() {
 		if err := GetHotListSyncTask(); err != nil {
 			global.Log.Error("Failed to get hot list:", zap.Error(err))
@@ -21,6 +35,55 @@ func RegisterScheduledTasks(c *cron.Cron) error {
 	}); err != nil {
 		return err
 	}
+	if _, err := c.AddFunc("@hourly", func() {
+		if err := UpdateSitemapSyncTask(); err != nil {
+			global.Log.Error("Failed to update sitemap:", zap.Error(err))
+		}
+	}); err != nil {
+		return err
+	}
+	if _, err := c.AddFunc("@every 1m", func() {
+		if err := PublishScheduledArticlesSyncTask(); err != nil {
+			global.Log.Error("Failed to publish scheduled articles:", zap.Error(err))
+		}
+	}); err != nil {
+		return err
+	}
+	if _, err := c.AddFunc("@every 10s", func() {
+		if err := DispatchEsOutboxSyncTask(); err != nil {
+			global.Log.Error("Failed to dispatch ES outbox events:", zap.Error(err))
+		}
+	}); err != nil {
+		return err
+	}
+	if _, err := c.AddFunc("@every 5s", func() {
+		if err := DeliverMailSyncTask(); err != nil {
+			global.Log.Error("Failed to deliver mails:", zap.Error(err))
+		}
+	}); err != nil {
+		return err
+	}
+	if _, err := c.AddFunc("@every 1m", func() {
+		if err := DispatchNewsletterSyncTask(); err != nil {
+			global.Log.Error("Failed to dispatch newsletters:", zap.Error(err))
+		}
+	}); err != nil {
+		return err
+	}
+	if _, err := c.AddFunc("@weekly", func() {
+		if err := NewsletterDigestSyncTask(); err != nil {
+			global.Log.Error("Failed to create newsletter digest:", zap.Error(err))
+		}
+	}); err != nil {
+		return err
+	}
+	if _, err := c.AddFunc("@weekly", func() {
+		if err := ReconcileCountersSyncTask(); err != nil {
+			global.Log.Error("Failed to reconcile counters:", zap.Error(err))
+		}
+	}); err != nil {
+		return err
+	}
 	if _, err := c.AddFunc("@daily", func() {
 		if err := GetCalendarSyncTask(); err != nil {
 			global.Log.Error("Failed to get calendar:", zap.Error(err))
@@ -0,0 +1,8 @@
+package task
+
+import "server/service"
+
+// DispatchEsOutboxSyncTask 投递发件箱中等待重试的 ES 操作
+func DispatchEsOutboxSyncTask() error {
+	return service.ServiceGroupApp.OutboxService.Dispatch()
+}
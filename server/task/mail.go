@@ -0,0 +1,8 @@
+package task
+
+import "server/service"
+
+// DeliverMailSyncTask 发送邮件队列中的邮件
+func DeliverMailSyncTask() error {
+	return service.ServiceGroupApp.MailService.Deliver()
+}
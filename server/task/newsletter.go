@@ -0,0 +1,13 @@
+package task
+
+import "server/service"
+
+// NewsletterDigestSyncTask 创建每周文章摘要
+func NewsletterDigestSyncTask() error {
+	return service.ServiceGroupApp.NewsletterService.Digest()
+}
+
+// DispatchNewsletterSyncTask 分批发送订阅邮件
+func DispatchNewsletterSyncTask() error {
+	return service.ServiceGroupApp.NewsletterService.Dispatch()
+}
@@ -0,0 +1,23 @@
+package task
+
+import (
+	"go.uber.org/zap"
+	"server/global"
+	"server/service"
+)
+
+// ReconcileCountersSyncTask 校对并修正收藏数、评论数以及类别和标签的文章数
+func ReconcileCountersSyncTask() error {
+	drifts, err := service.ServiceGroupApp.ReconcileService.Reconcile(true)
+	if err != nil {
+		return err
+	}
+	for _, d := range drifts {
+		global.Log.Warn("Counter drift fixed",
+			zap.String("kind", d.Kind),
+			zap.String("key", d.Key),
+			zap.Int("recorded", d.Recorded),
+			zap.Int("actual", d.Actual))
+	}
+	return nil
+}
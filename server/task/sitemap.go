@@ -0,0 +1,19 @@
+package task
+
+import (
+	"server/global"
+	"server/service"
+	"strings"
+)
+
+// UpdateSitemapSyncTask 重新生成站点地图并缓存到 Redis
+func UpdateSitemapSyncTask() error {
+	sitemapService := service.ServiceGroupApp.SitemapService
+
+	// 未配置网站地址时无法生成绝对链接，只清除缓存
+	if strings.TrimRight(global.Config.Website.SiteURL, "/") == "" {
+		return sitemapService.ClearSitemap()
+	}
+	_, err := sitemapService.RefreshSitemap()
+	return err
+}
@@ -0,0 +1,10 @@
+package task
+
+import (
+	"server/service"
+)
+
+// FlushTrafficSyncTask 将 Redis 中每小时的流量数据汇总到 MySQL
+func FlushTrafficSyncTask() error {
+	return service.ServiceGroupApp.TrafficService.Flush()
+}
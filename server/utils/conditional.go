@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WriteConditional 写入带有 ETag 和 Last-Modified 的响应，客户端缓存仍然有效时只返回 304
+func WriteConditional(c *gin.Context, contentType string, body []byte, lastModified time.Time) {
+	etag := `"` + MD5V(body) + `"`
+	c.Header("ETag", etag)
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	// 优先使用 If-None-Match 判断，没有时再使用 If-Modified-Since
+	if match := c.GetHeader("If-None-Match"); match != "" {
+		for _, tag := range strings.Split(match, ",") {
+			if tag = strings.TrimSpace(tag); tag == etag || tag == "W/"+etag || tag == "*" {
+				c.Status(http.StatusNotModified)
+				return
+			}
+		}
+	} else if since := c.GetHeader("If-Modified-Since"); since != "" && !lastModified.IsZero() {
+		if t, err := http.ParseTime(since); err == nil && !lastModified.Truncate(time.Second).After(t) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+
+	c.Data(http.StatusOK, contentType, body)
+}
@@ -0,0 +1,44 @@
+package contentFilter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"server/global"
+	"server/model/appTypes"
+	"strings"
+	"time"
+)
+
+// DuplicateFilter 同一用户在时间窗口内重复发布相同内容时按配置处理
+type DuplicateFilter struct {
+	Window time.Duration
+	Action appTypes.FilterAction
+}
+
+func (*DuplicateFilter) Name() string {
+	return "duplicate"
+}
+
+// Check 只检查是否重复，内容被接受后才由 Record 记录，被拒绝的内容不影响之后的发布
+func (f *DuplicateFilter) Check(input *Input) (*Hit, error) {
+	exists, err := global.Redis.Exists(f.key(input)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if exists == 0 {
+		return nil, nil
+	}
+	return &Hit{Rule: f.Name(), Action: f.Action, Reason: "重复发布相同内容"}, nil
+}
+
+// Record 记录已被接受的内容
+func (f *DuplicateFilter) Record(input *Input) error {
+	return global.Redis.Set(f.key(input), 1, f.Window).Err()
+}
+
+// key 内容在 Redis 中的键，忽略大小写和空白字符的差异
+func (f *DuplicateFilter) key(input *Input) string {
+	normalized := strings.ToLower(strings.Join(strings.Fields(input.Content), ""))
+	sum := sha256.Sum256([]byte(normalized))
+	return "content-filter-duplicate-" + input.Scene + "-" + input.UserUUID + "-" + hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,90 @@
+package contentFilter
+
+import (
+	"errors"
+	"server/model/appTypes"
+	"server/model/other"
+)
+
+// ErrRejected 内容被过滤规则拒绝
+var ErrRejected = errors.New("content rejected by filter")
+
+// Mask 打码时替换命中内容的字符串
+const Mask = "***"
+
+// Input 待过滤的内容
+type Input struct {
+	Scene    string // 场景，例如 comment、feedback
+	UserUUID string // 发布者的 uuid
+	Content  string // 内容，打码的规则会直接修改
+}
+
+// Hit 规则的命中结果
+type Hit = other.FilterHit
+
+// Filter 内容过滤规则，未命中时返回 nil
+type Filter interface {
+	Name() string
+	Check(input *Input) (*Hit, error)
+}
+
+// Recorder 内容最终没有被拒绝时需要记录状态的规则，例如记录已发布的内容用于检测重复
+type Recorder interface {
+	Record(input *Input) error
+}
+
+// Result 过滤结果，Action 为所有命中规则中最严格的处理方式
+type Result struct {
+	Action  appTypes.FilterAction
+	Content string
+	Hits    []Hit
+}
+
+// Pipeline 按顺序执行的过滤规则，命中拒绝的规则后不再继续检查
+type Pipeline []Filter
+
+func (p Pipeline) Run(input Input) (Result, error) {
+	result := Result{Action: appTypes.FilterPass}
+	for _, filter := range p {
+		hit, err := filter.Check(&input)
+		if err != nil {
+			return Result{}, err
+		}
+		if hit == nil {
+			continue
+		}
+		result.Hits = append(result.Hits, *hit)
+		if severity(hit.Action) > severity(result.Action) {
+			result.Action = hit.Action
+		}
+		if result.Action == appTypes.FilterReject {
+			break
+		}
+	}
+
+	if result.Action != appTypes.FilterReject {
+		for _, filter := range p {
+			if recorder, ok := filter.(Recorder); ok {
+				if err := recorder.Record(&input); err != nil {
+					return Result{}, err
+				}
+			}
+		}
+	}
+	result.Content = input.Content
+	return result, nil
+}
+
+// severity 处理方式的严格程度
+func severity(action appTypes.FilterAction) int {
+	switch action {
+	case appTypes.FilterMask:
+		return 1
+	case appTypes.FilterModerate:
+		return 2
+	case appTypes.FilterReject:
+		return 3
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,69 @@
+package contentFilter
+
+import (
+	"server/model/appTypes"
+	"strings"
+)
+
+// Keyword 敏感词及其处理方式
+type Keyword struct {
+	Word   string
+	Action appTypes.FilterAction
+}
+
+// KeywordFilter 敏感词过滤，需要打码的敏感词会被替换为 ***
+type KeywordFilter struct {
+	matcher *Matcher
+	actions []appTypes.FilterAction
+}
+
+func NewKeywordFilter(keywords []Keyword) *KeywordFilter {
+	words := make([]string, 0, len(keywords))
+	actions := make([]appTypes.FilterAction, 0, len(keywords))
+	for _, keyword := range keywords {
+		if strings.TrimSpace(keyword.Word) == "" {
+			continue
+		}
+		words = append(words, keyword.Word)
+		actions = append(actions, keyword.Action)
+	}
+	return &KeywordFilter{matcher: NewMatcher(words), actions: actions}
+}
+
+func (*KeywordFilter) Name() string {
+	return "keyword"
+}
+
+func (f *KeywordFilter) Check(input *Input) (*Hit, error) {
+	text := []rune(input.Content)
+	matches := f.matcher.FindAll(text)
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	action := appTypes.FilterPass
+	covered := make([]bool, len(text))
+	masked := false
+	seen := make(map[int]bool)
+	var words []string
+	for _, match := range matches {
+		wordAction := f.actions[match.Word]
+		if severity(wordAction) > severity(action) {
+			action = wordAction
+		}
+		if wordAction == appTypes.FilterMask {
+			for i := match.Start; i < match.End; i++ {
+				covered[i] = true
+			}
+			masked = true
+		}
+		if !seen[match.Word] {
+			seen[match.Word] = true
+			words = append(words, f.matcher.Word(match.Word))
+		}
+	}
+	if masked {
+		input.Content = maskRanges(text, covered)
+	}
+	return &Hit{Rule: f.Name(), Action: action, Reason: "命中敏感词：" + strings.Join(words, "、")}, nil
+}
@@ -0,0 +1,30 @@
+package contentFilter
+
+import (
+	"regexp"
+	"server/model/appTypes"
+	"strconv"
+)
+
+var linkPattern = regexp.MustCompile(`(?i)(?:https?://|www\.)[^\s<>"']+`)
+
+// LinkFilter 链接数量过多时按配置处理，打码时替换所有链接
+type LinkFilter struct {
+	Max    int
+	Action appTypes.FilterAction
+}
+
+func (*LinkFilter) Name() string {
+	return "link"
+}
+
+func (f *LinkFilter) Check(input *Input) (*Hit, error) {
+	count := len(linkPattern.FindAllStringIndex(input.Content, -1))
+	if count <= f.Max {
+		return nil, nil
+	}
+	if f.Action == appTypes.FilterMask {
+		input.Content = linkPattern.ReplaceAllString(input.Content, Mask)
+	}
+	return &Hit{Rule: f.Name(), Action: f.Action, Reason: "包含 " + strconv.Itoa(count) + " 个链接"}, nil
+}
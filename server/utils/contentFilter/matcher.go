@@ -0,0 +1,116 @@
+package contentFilter
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Matcher 基于 Aho-Corasick 自动机的多关键词匹配器，匹配时忽略大小写
+type Matcher struct {
+	nodes []acNode
+	words []string
+}
+
+type acNode struct {
+	next   map[rune]int
+	fail   int
+	output []int // 以该节点结尾的关键词下标
+}
+
+// Match 一次匹配，Start 和 End 为命中内容在 rune 切片中的范围
+type Match struct {
+	Start int
+	End   int
+	Word  int
+}
+
+// NewMatcher 根据关键词构建自动机
+func NewMatcher(words []string) *Matcher {
+	m := &Matcher{nodes: []acNode{{next: map[rune]int{}}}}
+	for _, word := range words {
+		word = strings.TrimSpace(word)
+		if word == "" {
+			continue
+		}
+		cur := 0
+		for _, r := range word {
+			r = unicode.ToLower(r)
+			child, ok := m.nodes[cur].next[r]
+			if !ok {
+				m.nodes = append(m.nodes, acNode{next: map[rune]int{}})
+				child = len(m.nodes) - 1
+				m.nodes[cur].next[r] = child
+			}
+			cur = child
+		}
+		m.nodes[cur].output = append(m.nodes[cur].output, len(m.words))
+		m.words = append(m.words, word)
+	}
+
+	// 广度优先构建失败指针
+	var queue []int
+	for _, child := range m.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for r, child := range m.nodes[cur].next {
+			fail := m.nodes[cur].fail
+			for fail > 0 {
+				if _, ok := m.nodes[fail].next[r]; ok {
+					break
+				}
+				fail = m.nodes[fail].fail
+			}
+			if next, ok := m.nodes[fail].next[r]; ok && next != child {
+				m.nodes[child].fail = next
+			}
+			m.nodes[child].output = append(m.nodes[child].output, m.nodes[m.nodes[child].fail].output...)
+			queue = append(queue, child)
+		}
+	}
+	return m
+}
+
+// Word 返回下标对应的关键词
+func (m *Matcher) Word(i int) string {
+	return m.words[i]
+}
+
+// FindAll 查找文本中所有命中的关键词
+func (m *Matcher) FindAll(text []rune) []Match {
+	var matches []Match
+	cur := 0
+	for i, r := range text {
+		r = unicode.ToLower(r)
+		for cur > 0 {
+			if _, ok := m.nodes[cur].next[r]; ok {
+				break
+			}
+			cur = m.nodes[cur].fail
+		}
+		cur = m.nodes[cur].next[r]
+		for _, w := range m.nodes[cur].output {
+			length := len([]rune(m.words[w]))
+			matches = append(matches, Match{Start: i + 1 - length, End: i + 1, Word: w})
+		}
+	}
+	return matches
+}
+
+// maskRanges 将命中的范围替换为 ***，相邻或重叠的范围合并为一个
+func maskRanges(text []rune, covered []bool) string {
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		if !covered[i] {
+			b.WriteRune(text[i])
+			continue
+		}
+		b.WriteString(Mask)
+		for i+1 < len(text) && covered[i+1] {
+			i++
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,31 @@
+package contentFilter
+
+import (
+	"server/model/appTypes"
+	"server/utils"
+	"strconv"
+	"time"
+)
+
+// RateFilter 同一用户在时间窗口内发布次数超过限制时按配置处理
+type RateFilter struct {
+	Limit  int
+	Window time.Duration
+	Action appTypes.FilterAction
+}
+
+func (*RateFilter) Name() string {
+	return "rate"
+}
+
+func (f *RateFilter) Check(input *Input) (*Hit, error) {
+	key := "content-filter-rate-" + input.Scene + "-" + input.UserUUID
+	count, err := utils.IncrWindow(key, f.Window)
+	if err != nil {
+		return nil, err
+	}
+	if count <= int64(f.Limit) {
+		return nil, nil
+	}
+	return &Hit{Rule: f.Name(), Action: f.Action, Reason: strconv.FormatInt(int64(f.Window/time.Second), 10) + " 秒内发布了 " + strconv.FormatInt(count, 10) + " 次"}, nil
+}
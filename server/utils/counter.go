@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"github.com/go-redis/redis"
+	"server/global"
+	"time"
+)
+
+// incrWindow 原子地增加计数，计数没有过期时间时设置过期时间，避免 INCR 和 EXPIRE 之间出错导致计数永不过期
+var incrWindow = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+if redis.call('PTTL', KEYS[1]) < 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return count
+`)
+
+// IncrWindow 增加固定时间窗口内的计数并返回增加后的值，窗口从第一次计数开始
+func IncrWindow(key string, window time.Duration) (int64, error) {
+	return incrWindow.Run(&global.Redis, []string{key}, window.Milliseconds()).Int64()
+}
@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"server/model/other"
+	"strings"
+)
+
+// diffMaxLines 去掉首尾相同的行后，新旧文本合计超过该行数时不再逐行比较，整体视为删除后新增
+const diffMaxLines = 10000
+
+// DiffLines 基于最长公共子序列，逐行比较新旧文本，返回行级差异
+// 先去掉首尾相同的行，再用线性空间的 Hirschberg 算法比较剩余部分
+func DiffLines(oldText, newText string) []other.DiffLine {
+	oldLines := strings.Split(oldText, "\n")
+	newLines := strings.Split(newText, "\n")
+	n, m := len(oldLines), len(newLines)
+
+	prefix := 0
+	for prefix < n && prefix < m && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < n-prefix && suffix < m-prefix && oldLines[n-1-suffix] == newLines[m-1-suffix] {
+		suffix++
+	}
+	a, b := oldLines[prefix:n-suffix], newLines[prefix:m-suffix]
+
+	var ops []byte
+	if len(a)+len(b) > diffMaxLines {
+		ops = appendOps(appendOps(ops, 'd', len(a)), 'i', len(b))
+	} else {
+		ops = lcsOps(a, b, ops)
+	}
+
+	diff := make([]other.DiffLine, 0, prefix+len(ops)+suffix)
+	for i := 0; i < prefix; i++ {
+		diff = append(diff, other.DiffLine{Type: "equal", Content: oldLines[i], OldLine: i + 1, NewLine: i + 1})
+	}
+	i, j := prefix, prefix
+	for _, op := range ops {
+		switch op {
+		case 'e':
+			diff = append(diff, other.DiffLine{Type: "equal", Content: oldLines[i], OldLine: i + 1, NewLine: j + 1})
+			i++
+			j++
+		case 'd':
+			diff = append(diff, other.DiffLine{Type: "delete", Content: oldLines[i], OldLine: i + 1})
+			i++
+		default:
+			diff = append(diff, other.DiffLine{Type: "insert", Content: newLines[j], NewLine: j + 1})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		diff = append(diff, other.DiffLine{Type: "equal", Content: oldLines[i], OldLine: i + 1, NewLine: j + 1})
+		j++
+	}
+	return diff
+}
+
+// lcsOps 使用 Hirschberg 算法计算把 a 变为 b 的编辑序列，e 表示相同，d 表示删除，i 表示新增
+func lcsOps(a, b []string, ops []byte) []byte {
+	switch {
+	case len(a) == 0:
+		return appendOps(ops, 'i', len(b))
+	case len(b) == 0:
+		return appendOps(ops, 'd', len(a))
+	case len(a) == 1:
+		for k, line := range b {
+			if line == a[0] {
+				ops = appendOps(ops, 'i', k)
+				ops = append(ops, 'e')
+				return appendOps(ops, 'i', len(b)-k-1)
+			}
+		}
+		return appendOps(append(ops, 'd'), 'i', len(b))
+	}
+
+	// 在 a 的中点处寻找 b 的最优切分位置，使前后两半的公共子序列长度之和最大
+	mid := len(a) / 2
+	forward := lcsForward(a[:mid], b)
+	backward := lcsBackward(a[mid:], b)
+	split := 0
+	for k := 1; k <= len(b); k++ {
+		if forward[k]+backward[k] > forward[split]+backward[split] {
+			split = k
+		}
+	}
+	ops = lcsOps(a[:mid], b[:split], ops)
+	return lcsOps(a[mid:], b[split:], ops)
+}
+
+// lcsForward 返回 a 与 b[:k] 的最长公共子序列长度，k 从 0 到 len(b)
+func lcsForward(a, b []string) []int {
+	prev, cur := make([]int, len(b)+1), make([]int, len(b)+1)
+	for i := range a {
+		for j := range b {
+			if a[i] == b[j] {
+				cur[j+1] = prev[j] + 1
+			} else {
+				cur[j+1] = max(prev[j+1], cur[j])
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev
+}
+
+// lcsBackward 返回 a 与 b[k:] 的最长公共子序列长度，k 从 0 到 len(b)
+func lcsBackward(a, b []string) []int {
+	prev, cur := make([]int, len(b)+1), make([]int, len(b)+1)
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				cur[j] = prev[j+1] + 1
+			} else {
+				cur[j] = max(prev[j], cur[j+1])
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev
+}
+
+// appendOps 追加 count 个相同的编辑操作
+func appendOps(ops []byte, op byte, count int) []byte {
+	for ; count > 0; count-- {
+		ops = append(ops, op)
+	}
+	return ops
+}
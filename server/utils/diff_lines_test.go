@@ -0,0 +1,192 @@
+package utils
+
+import (
+	"fmt"
+	"math/rand"
+	"server/model/other"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldText string
+		newText string
+		// 每行差异的简写，= 表示相同，- 表示删除，+ 表示新增
+		want []string
+	}{
+		{
+			name: "both empty",
+			want: []string{"="},
+		},
+		{
+			name:    "identical",
+			oldText: "a\nb\nc",
+			newText: "a\nb\nc",
+			want:    []string{"=a", "=b", "=c"},
+		},
+		{
+			name:    "from empty",
+			newText: "a\nb",
+			want:    []string{"-", "+a", "+b"},
+		},
+		{
+			name:    "insert only",
+			oldText: "a\nd",
+			newText: "a\nb\nc\nd",
+			want:    []string{"=a", "+b", "+c", "=d"},
+		},
+		{
+			name:    "delete only",
+			oldText: "a\nb\nc\nd",
+			newText: "a\nd",
+			want:    []string{"=a", "-b", "-c", "=d"},
+		},
+		{
+			name:    "interleaved edits",
+			oldText: "a\nb\nc\nd\ne",
+			newText: "a\nx\nc\ny\ne",
+			want:    []string{"=a", "-b", "+x", "=c", "-d", "+y", "=e"},
+		},
+		{
+			name:    "moved line",
+			oldText: "a\nb\nc",
+			newText: "b\nc\na",
+			want:    []string{"-a", "=b", "=c", "+a"},
+		},
+		{
+			name:    "all lines changed",
+			oldText: "a\nb",
+			newText: "c\nd",
+			want:    []string{"-a", "-b", "+c", "+d"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := DiffLines(tt.oldText, tt.newText)
+			checkDiff(t, tt.oldText, tt.newText, diff)
+			if got := shortDiff(diff); !slices.Equal(got, tt.want) {
+				t.Errorf("DiffLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffLinesOverLimit(t *testing.T) {
+	// 首尾相同的行之间各有超过限制一半的不同行，中间的相同行在超过限制时不再比较
+	half := diffMaxLines/4 + 1
+	block := func(prefix string) []string {
+		var lines []string
+		for i := 0; i < half; i++ {
+			lines = append(lines, fmt.Sprintf("%s %d", prefix, i))
+		}
+		lines = append(lines, "same")
+		for i := half; i < 2*half; i++ {
+			lines = append(lines, fmt.Sprintf("%s %d", prefix, i))
+		}
+		return lines
+	}
+	oldBlock, newBlock := block("old"), block("new")
+	oldText := strings.Join(append(append([]string{"head"}, oldBlock...), "tail"), "\n")
+	newText := strings.Join(append(append([]string{"head"}, newBlock...), "tail"), "\n")
+
+	diff := DiffLines(oldText, newText)
+	checkDiff(t, oldText, newText, diff)
+
+	want := []string{"=head"}
+	for _, line := range oldBlock {
+		want = append(want, "-"+line)
+	}
+	for _, line := range newBlock {
+		want = append(want, "+"+line)
+	}
+	want = append(want, "=tail")
+	if got := shortDiff(diff); !slices.Equal(got, want) {
+		t.Errorf("DiffLines() has %d lines, want %d lines with the changed block deleted and then inserted", len(got), len(want))
+	}
+}
+
+func TestDiffLinesMinimal(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 200; n++ {
+		oldText, newText := randomLines(r), randomLines(r)
+		diff := DiffLines(oldText, newText)
+		checkDiff(t, oldText, newText, diff)
+
+		equal := 0
+		for _, line := range diff {
+			if line.Type == "equal" {
+				equal++
+			}
+		}
+		if want := lcsLength(strings.Split(oldText, "\n"), strings.Split(newText, "\n")); equal != want {
+			t.Fatalf("DiffLines(%q, %q) keeps %d lines, want the longest common subsequence of %d lines", oldText, newText, equal, want)
+		}
+	}
+}
+
+// checkDiff 检查差异能否还原出新旧文本，并且行号连续
+func checkDiff(t *testing.T, oldText, newText string, diff []other.DiffLine) {
+	t.Helper()
+	var oldLines, newLines []string
+	for _, line := range diff {
+		if line.Type != "insert" {
+			oldLines = append(oldLines, line.Content)
+			if line.OldLine != len(oldLines) {
+				t.Fatalf("old line number = %d, want %d", line.OldLine, len(oldLines))
+			}
+		}
+		if line.Type != "delete" {
+			newLines = append(newLines, line.Content)
+			if line.NewLine != len(newLines) {
+				t.Fatalf("new line number = %d, want %d", line.NewLine, len(newLines))
+			}
+		}
+	}
+	if got := strings.Join(oldLines, "\n"); got != oldText {
+		t.Fatalf("old text = %q, want %q", got, oldText)
+	}
+	if got := strings.Join(newLines, "\n"); got != newText {
+		t.Fatalf("new text = %q, want %q", got, newText)
+	}
+}
+
+// shortDiff 把差异转换为简写形式
+func shortDiff(diff []other.DiffLine) []string {
+	prefix := map[string]string{"equal": "=", "delete": "-", "insert": "+"}
+	got := make([]string, 0, len(diff))
+	for _, line := range diff {
+		got = append(got, prefix[line.Type]+line.Content)
+	}
+	return got
+}
+
+// randomLines 生成由少量不同行组成的随机文本，使新旧文本有较多相同的行
+func randomLines(r *rand.Rand) string {
+	lines := make([]string, r.Intn(12))
+	for i := range lines {
+		lines[i] = string(rune('a' + r.Intn(4)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// lcsLength 使用完整的动态规划表计算最长公共子序列长度
+func lcsLength(a, b []string) int {
+	dp := make([][]int, len(a)+1)
+	for i := range dp {
+		dp[i] = make([]int, len(b)+1)
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				dp[i][j] = dp[i-1][j-1] + 1
+			} else {
+				dp[i][j] = max(dp[i-1][j], dp[i][j-1])
+			}
+		}
+	}
+	return dp[len(a)][len(b)]
+}
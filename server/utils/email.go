@@ -12,11 +12,18 @@ import (
 // Email 发送电子邮件
 func Email(To, subject string, body string) error {
 	to := strings.Split(To, ",") // 将收件人邮箱地址按逗号拆分成多个地址
-	return send(to, subject, body)
+	return send(to, subject, body, "", nil)
 }
 
-// send 执行邮件发送操作
-func send(to []string, subject string, body string) error {
+// EmailWithText 发送同时包含 HTML 和纯文本正文的电子邮件，客户端可以自行选择展示的版本
+// headers 为额外的邮件头，例如订阅邮件的 List-Unsubscribe
+func EmailWithText(To, subject, html, text string, headers map[string]string) error {
+	to := strings.Split(To, ",")
+	return send(to, subject, html, text, headers)
+}
+
+// send 执行邮件发送操作，text 为空时只发送 HTML 正文
+func send(to []string, subject string, body string, text string, headers map[string]string) error {
 	emailCfg := global.Config.Email // 获取全局配置中的邮件设置
     
 	from := emailCfg.From
@@ -43,6 +50,12 @@ func send(to []string, subject string, body string) error {
 	e.To = to
 	e.Subject = subject
 	e.HTML = []byte(body)
+	if text != "" {
+		e.Text = []byte(text)
+	}
+	for key, value := range headers {
+		e.Headers.Set(key, value)
+	}
 
 	// 定义错误变量
 	var err error
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"context"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"server/global"
+)
+
+// EsScroll 使用滚动查询遍历索引中匹配的所有文档，每批结果交给 fn 处理
+func EsScroll(ctx context.Context, index string, query *types.Query, sourceIncludes []string, fn func(hits []types.Hit) error) error {
+	res, err := global.ESClient.Search().
+		Index(index).
+		Scroll("1m").
+		Size(1000).
+		Query(query).
+		SourceIncludes_(sourceIncludes...).
+		Do(ctx)
+	if err != nil {
+		return err
+	}
+	if err := fn(res.Hits.Hits); err != nil {
+		return err
+	}
+
+	scrollID := res.ScrollId_
+	for scrollID != nil && len(res.Hits.Hits) > 0 {
+		scrollRes, err := global.ESClient.Scroll().ScrollId(*scrollID).Scroll("1m").Do(ctx)
+		if err != nil {
+			return err
+		}
+		if len(scrollRes.Hits.Hits) == 0 {
+			break
+		}
+		if err := fn(scrollRes.Hits.Hits); err != nil {
+			return err
+		}
+		scrollID = scrollRes.ScrollId_
+	}
+
+	// 清除滚动查询，释放 Elasticsearch 上的资源
+	if scrollID != nil {
+		_, _ = global.ESClient.ClearScroll().ScrollId(*scrollID).Do(ctx)
+	}
+	return nil
+}
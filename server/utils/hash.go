@@ -1,7 +1,9 @@
 package utils
 
 import (
+	"crypto/hmac"
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/hex"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -22,4 +24,16 @@ func MD5V(str []byte, b ...byte) string {
 	h := md5.New()
 	h.Write(str)
 	return hex.EncodeToString(h.Sum(b))
-}
\ No newline at end of file
+}
+
+// HMACSign 使用 HMAC-SHA256 对消息签名
+func HMACSign(secret, message string) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(message))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// HMACVerify 校验消息的 HMAC-SHA256 签名
+func HMACVerify(secret, message, signature string) bool {
+	return hmac.Equal([]byte(HMACSign(secret, message)), []byte(signature))
+}
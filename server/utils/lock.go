@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"github.com/go-redis/redis"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+	"server/global"
+	"time"
+)
+
+// unlockScript 只有锁仍由自己持有时才删除，避免锁过期后删除其他实例的锁
+var unlockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// Lock 获取基于 Redis 的互斥锁，用于多实例部署时保证同一任务只有一个实例在执行
+// 获取成功时返回释放锁的函数，锁已被其他实例持有时 ok 为 false，锁在 ttl 后自动过期
+func Lock(key string, ttl time.Duration) (unlock func(), ok bool, err error) {
+	token := uuid.Must(uuid.NewV4()).String()
+	ok, err = global.Redis.SetNX(key, token, ttl).Result()
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	return func() {
+		if err := unlockScript.Run(&global.Redis, []string{key}, token).Err(); err != nil {
+			global.Log.Error("Failed to release lock:", zap.String("key", key), zap.Error(err))
+		}
+	}, true, nil
+}
@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"bytes"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"html"
+	"math"
+	"regexp"
+	"server/model/other"
+	"strconv"
+	"unicode"
+)
+
+const (
+	cjkPerMinute   = 400 // 每分钟阅读的中日韩文字数
+	wordsPerMinute = 200 // 每分钟阅读的单词数
+)
+
+// markdown Markdown 解析器，支持 GFM 语法，原始 HTML 交由 markdownPolicy 清洗
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	goldmark.WithRendererOptions(goldmarkhtml.WithUnsafe()),
+)
+
+// markdownPolicy 渲染结果的 HTML 清洗策略
+var markdownPolicy = func() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	// 标题锚点，允许中文
+	p.AllowAttrs("id").Matching(regexp.MustCompile(`^[\p{L}\p{N}\-_]+$`)).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	// 代码块语言，用于前端高亮
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	// 任务列表
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	return p
+}()
+
+// headingIDs 根据标题文本生成稳定的锚点，重复的锚点依次追加 -1、-2 等后缀
+type headingIDs struct {
+	values map[string]bool
+}
+
+func (ids *headingIDs) Generate(value []byte, _ ast.NodeKind) []byte {
+	base := Slugify(string(value))
+	if base == "" {
+		base = "heading"
+	}
+	id := base
+	for i := 1; ids.values[id]; i++ {
+		id = base + "-" + strconv.Itoa(i)
+	}
+	ids.values[id] = true
+	return []byte(id)
+}
+
+func (ids *headingIDs) Put(value []byte) {
+	ids.values[string(value)] = true
+}
+
+// RenderMarkdown 将 Markdown 渲染为清洗后的 HTML，同时提取标题目录、字数和预计阅读时间
+func RenderMarkdown(source string) (other.Markdown, error) {
+	src := []byte(source)
+	ctx := parser.NewContext(parser.WithIDs(&headingIDs{values: map[string]bool{}}))
+	doc := markdown.Parser().Parse(text.NewReader(src), parser.WithContext(ctx))
+
+	var buf bytes.Buffer
+	if err := markdown.Renderer().Render(&buf, src, doc); err != nil {
+		return other.Markdown{}, err
+	}
+
+	result := other.Markdown{
+		HTML: markdownPolicy.Sanitize(buf.String()),
+		TOC:  []other.TOCItem{},
+	}
+
+	// 提取标题目录，较低级别的标题挂在前一个较高级别的标题下
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := n.(*ast.Heading)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+		var b bytes.Buffer
+		nodeText(heading, src, &b)
+		item := other.TOCItem{Text: b.String(), Level: heading.Level}
+		if id, ok := heading.AttributeString("id"); ok {
+			if v, ok := id.([]byte); ok {
+				item.ID = string(v)
+			}
+		}
+		list := &result.TOC
+		for len(*list) > 0 && (*list)[len(*list)-1].Level < item.Level {
+			list = &(*list)[len(*list)-1].Children
+		}
+		*list = append(*list, item)
+		return ast.WalkSkipChildren, nil
+	})
+
+	// 统计字数时去掉所有标签
+	plain := html.UnescapeString(bluemonday.StrictPolicy().Sanitize(result.HTML))
+	cjk, words := CountWords(plain)
+	result.WordCount = cjk + words
+	if result.WordCount > 0 {
+		result.ReadingTime = int(math.Ceil(float64(cjk)/cjkPerMinute + float64(words)/wordsPerMinute))
+	}
+	return result, nil
+}
+
+// CountWords 统计文本字数，分别返回中日韩文字数和其他语言的单词数
+func CountWords(s string) (cjk int, words int) {
+	inWord := false
+	for _, r := range s {
+		switch {
+		case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul):
+			cjk++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				words++
+				inWord = true
+			}
+		default:
+			inWord = false
+		}
+	}
+	return cjk, words
+}
+
+// nodeText 获取节点下的纯文本
+func nodeText(n ast.Node, source []byte, b *bytes.Buffer) {
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch t := c.(type) {
+		case *ast.Text:
+			b.Write(t.Segment.Value(source))
+			if t.SoftLineBreak() {
+				b.WriteByte(' ')
+			}
+		case *ast.String:
+			b.Write(t.Value)
+		default:
+			nodeText(c, source, b)
+		}
+	}
+}
@@ -56,4 +56,10 @@ func ParseDuration(d string) (time.Duration, error) {
 
 	// 返回总的持续时间
 	return totalDuration, nil
-}
\ No newline at end of file
+}
+
+// ParseDateTime 解析 "2006-01-02 15:04:05" 格式的本地时间字符串，格式无效时返回零值
+func ParseDateTime(s string) time.Time {
+	t, _ := time.ParseInLocation("2006-01-02 15:04:05", s, time.Local)
+	return t
+}
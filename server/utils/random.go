@@ -1,14 +1,27 @@
 package utils
 
 import (
+	"crypto/rand"
+	"encoding/base64"
 	"fmt"
 	"math"
-	"math/rand"
-	"time"
+	"math/big"
 )
 
-// GenerateVerificationCode 生成一个指定长度的随机验证码
+// GenerateVerificationCode 生成一个指定长度的随机验证码，使用 crypto/rand 避免验证码被预测
 func GenerateVerificationCode(length int) string {
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
-	return fmt.Sprintf("%0*d", length, r.Intn(int(math.Pow10(length))))
-}
\ No newline at end of file
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(math.Pow10(length))))
+	if err != nil {
+		panic(err)
+	}
+	return fmt.Sprintf("%0*d", length, n.Int64())
+}
+
+// GenerateSecret 生成一个包含 size 字节随机数据的密钥
+func GenerateSecret(size int) string {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"github.com/gin-gonic/gin"
+	"server/global"
+	"strings"
+)
+
+// SiteURL 获取网站地址，未配置时根据当前请求推断
+func SiteURL(c *gin.Context) string {
+	if siteURL := strings.TrimRight(global.Config.Website.SiteURL, "/"); siteURL != "" {
+		return siteURL
+	}
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host
+}
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// slugMaxLength 别名的最大字符数
+const slugMaxLength = 80
+
+// Slugify 将标题转换为可读的 URL 别名
+// 保留字母（包括中文）和数字并转为小写，其余字符统一替换为连字符，连续的连字符会被合并
+func Slugify(title string) string {
+	var builder strings.Builder
+	count := 0
+	hyphen := false
+	for _, r := range strings.ToLower(strings.TrimSpace(title)) {
+		if count >= slugMaxLength {
+			break
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if hyphen && builder.Len() > 0 {
+				builder.WriteRune('-')
+				count++
+			}
+			builder.WriteRune(r)
+			count++
+			hyphen = false
+		} else {
+			hyphen = true
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	"net/url"
+	"server/model/other"
+	"strconv"
+	"strings"
+)
+
+// GetVisitor 获取当前请求的访客信息
+func GetVisitor(c *gin.Context) other.Visitor {
+	return other.Visitor{
+		ID:       VisitorID(c),
+		Referrer: ReferrerHost(c.Request.Referer()),
+	}
+}
+
+// VisitorID 生成访客标识，已登录用户使用用户 ID，游客使用 IP 和 User-Agent 生成的指纹
+func VisitorID(c *gin.Context) string {
+	if token := GetAccessToken(c); token != "" {
+		if claims, err := NewJWT().ParseAccessToken(token); err == nil {
+			return "user:" + strconv.FormatUint(uint64(claims.UserID), 10)
+		}
+	}
+	sum := sha256.Sum256([]byte(c.ClientIP() + "|" + c.Request.UserAgent()))
+	return "guest:" + hex.EncodeToString(sum[:8])
+}
+
+// ReferrerHost 从 Referer 中取出来源域名
+func ReferrerHost(referer string) string {
+	u, err := url.Parse(referer)
+	if err != nil {
+		return ""
+	}
+	host := strings.ToLower(u.Hostname())
+	if len(host) > 255 {
+		return ""
+	}
+	return host
+}